@@ -0,0 +1,112 @@
+package corelog
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// maxAdminBodySize is the maximum size of admin request bodies.
+const maxAdminBodySize = 1 << 20
+
+// AdminHandler returns an http.Handler for inspecting and changing
+// the config of the default registry at runtime.
+func AdminHandler() http.Handler {
+	return defaultRegistry.AdminHandler()
+}
+
+// AdminHandler returns an http.Handler for inspecting and changing
+// the config at runtime.
+//
+// The logger name is set with the name query parameter, and requests
+// without a name change the config for all loggers.
+//
+//   - GET returns the config and all config overrides, or the config of the named logger.
+//   - PUT replaces the config or the config override with the fields in the request body.
+//   - PATCH sets the fields in the request body on the config or config override.
+//   - DELETE removes the config override of the named logger.
+//
+// Overrides only set the fields in the request body, and all other fields
+// inherit their values from the global config and parent loggers.
+//
+// Configs are JSON objects with the same keys as config overrides, e.g.
+// {"level": "debug", "format": "json"}. Successful requests respond with
+// the resulting config, and request bodies are limited to 1 MiB.
+func (r *Registry) AdminHandler() http.Handler {
+	return http.HandlerFunc(r.serveAdmin)
+}
+
+func (r *Registry) serveAdmin(w http.ResponseWriter, req *http.Request) {
+	name := req.URL.Query().Get("name")
+	switch req.Method {
+	case http.MethodGet:
+		// return the effective config of the named logger
+	case http.MethodPut, http.MethodPatch:
+		values := make(map[string]any)
+		err := json.NewDecoder(http.MaxBytesReader(w, req.Body, maxAdminBodySize)).Decode(&values)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		if err == nil {
+			err = r.updateConfig(name, values, req.Method == http.MethodPatch)
+		}
+		if err != nil {
+			http.Error(w, "invalid config: "+strings.ReplaceAll(err.Error(), "\n", "; "), http.StatusBadRequest)
+			return
+		}
+	case http.MethodDelete:
+		if name == "" {
+			http.Error(w, "logger name is required", http.StatusBadRequest)
+			return
+		}
+		r.RemoveConfigOverride(name)
+	default:
+		w.Header().Set("Allow", "GET, PUT, PATCH, DELETE")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body any = configValues(r.GetConfig(name))
+	if name == "" {
+		r.mutex.RLock()
+		body = r.state.dump()
+		r.mutex.RUnlock()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// updateConfig sets the values on the config or the config override with
+// the given name, which are replaced unless patch is true.
+func (r *Registry) updateConfig(name string, values map[string]any, patch bool) error {
+	return r.change(func() error {
+		if name == "" {
+			var config Config
+			if patch {
+				config = r.state.config
+			}
+			if err := errors.Join(setConfigValues(&config, nil, "", "", "", values)...); err != nil {
+				return err
+			}
+			r.state.config = config
+			return nil
+		}
+		if err := checkOverrideName(name); err != nil {
+			return &ConfigError{Name: name, Err: err}
+		}
+		override := ConfigOverride{Name: name}
+		if existing, ok := r.state.overrides[name]; ok && patch {
+			override.Config = existing.Config
+			override.Fields = slices.Clone(existing.Fields)
+		}
+		if err := errors.Join(setConfigValues(&override.Config, &override.Fields, "", "", "", values)...); err != nil {
+			return err
+		}
+		r.state.setOverride(override)
+		return nil
+	})
+}
@@ -0,0 +1,118 @@
+package corelog
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminHandlerGet(t *testing.T) {
+	restoreConfig(t)
+	SetConfig(Config{Level: LevelError})
+	SetConfigOverride("admin", Config{Level: LevelDebug})
+
+	res := serveAdminRequest(t, http.MethodGet, "/", "")
+	require.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "application/json", res.Header().Get("Content-Type"))
+
+	var body configDump
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+	assert.Equal(t, LevelError, body.Config["level"])
+	assert.Equal(t, false, body.Config["source"])
+	assert.Equal(t, LevelDebug, body.Overrides["admin"]["level"])
+
+	res = serveAdminRequest(t, http.MethodGet, "/?name=admin", "")
+	require.Equal(t, http.StatusOK, res.Code)
+
+	var config map[string]any
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&config))
+	assert.Equal(t, LevelDebug, config["level"])
+}
+
+func TestAdminHandlerWithAttrs(t *testing.T) {
+	restoreConfig(t)
+	SetConfig(Config{})
+
+	res := serveAdminRequest(t, http.MethodPut, "/", `{"level": "info", "attr": {"service": "defradb"}, "attr.env": "prod"}`)
+	require.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, Config{Level: LevelInfo, Attrs: map[string]string{"service": "defradb", "env": "prod"}}, GetConfig(""))
+
+	res = serveAdminRequest(t, http.MethodGet, "/?name=attrs", "")
+	require.Equal(t, http.StatusOK, res.Code)
+	assert.Contains(t, res.Body.String(), `"attr.env":"prod","attr.service":"defradb"`)
+}
+
+func TestAdminHandlerPutAndPatch(t *testing.T) {
+	restoreConfig(t)
+	SetConfig(Config{Level: LevelError, Format: FormatJSON})
+
+	res := serveAdminRequest(t, http.MethodPatch, "/?name=admin", `{"level": "debug"}`)
+	require.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, LevelDebug, GetConfig("admin").Level)
+	assert.Equal(t, FormatJSON, GetConfig("admin").Format)
+
+	res = serveAdminRequest(t, http.MethodPut, "/?name=admin", `{"output": "stdout"}`)
+	require.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, ConfigOverride{Name: "admin", Config: Config{Output: OutputStdout}, Fields: []string{"output"}}, defaultRegistry.state.overrides["admin"])
+	assert.Equal(t, Config{Level: LevelError, Format: FormatJSON, Output: OutputStdout}, GetConfig("admin"))
+
+	res = serveAdminRequest(t, http.MethodPatch, "/?name=admin", `{"source": false}`)
+	require.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, []string{"output", "source"}, defaultRegistry.state.overrides["admin"].Fields)
+
+	res = serveAdminRequest(t, http.MethodPatch, "/", `{"level": "warn"}`)
+	require.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, LevelWarn, GetConfig("").Level)
+	assert.Equal(t, FormatJSON, GetConfig("").Format)
+}
+
+func TestAdminHandlerDelete(t *testing.T) {
+	restoreConfig(t)
+	SetConfig(Config{Level: LevelError})
+	SetConfigOverride("admin", Config{Level: LevelDebug})
+
+	res := serveAdminRequest(t, http.MethodDelete, "/?name=admin", "")
+	require.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, LevelError, GetConfig("admin").Level)
+
+	res = serveAdminRequest(t, http.MethodDelete, "/", "")
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+}
+
+func TestAdminHandlerWithInvalidRequest(t *testing.T) {
+	restoreConfig(t)
+	SetConfig(Config{Level: LevelError})
+
+	res := serveAdminRequest(t, http.MethodPatch, "/", `{"level": "debug", "levle": "info"}`)
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.Contains(t, res.Body.String(), `key "levle": unknown config key`)
+	assert.Equal(t, LevelError, GetConfig("").Level)
+
+	body := `{"level": "debug", "template": "` + strings.Repeat("x", maxAdminBodySize) + `"}`
+	res = serveAdminRequest(t, http.MethodPut, "/", body)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, res.Code)
+	assert.Equal(t, LevelError, GetConfig("").Level)
+
+	res = serveAdminRequest(t, http.MethodPut, "/?name=re:(", `{"level": "debug"}`)
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.Contains(t, res.Body.String(), `override "re:(": invalid name pattern`)
+	_, ok := defaultRegistry.state.overrides["re:("]
+	assert.False(t, ok)
+
+	res = serveAdminRequest(t, http.MethodPost, "/", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, res.Code)
+	assert.Equal(t, "GET, PUT, PATCH, DELETE", res.Header().Get("Allow"))
+}
+
+// serveAdminRequest serves the request with the admin handler and returns the response.
+func serveAdminRequest(t *testing.T, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	res := httptest.NewRecorder()
+	AdminHandler().ServeHTTP(res, req)
+	return res
+}
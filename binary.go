@@ -0,0 +1,641 @@
+package corelog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+	"github.com/vmihailenco/msgpack/v5/msgpcode"
+)
+
+// maxRecordSize is the maximum size of a binary record that will be decoded.
+const maxRecordSize = 64 << 20
+
+var (
+	cborEncMode, _ = cbor.EncOptions{
+		Time:    cbor.TimeRFC3339Nano,
+		TimeTag: cbor.EncTagRequired,
+	}.EncMode()
+	cborDecMode, _ = cbor.DecOptions{
+		TimeTagToAny: cbor.TimeTagToTime,
+	}.DecMode()
+)
+
+// Durations and unsigned integers are wrapped in a CBOR tag or a msgpack
+// extension type so that they decode to the same slog.Kind they were
+// logged with. Plain integers always decode as int64.
+const (
+	cborTagDuration = 60000
+	cborTagUint64   = 60001
+
+	msgpackExtDuration = 1
+	msgpackExtUint64   = 2
+)
+
+// binaryEncoder encodes the values of a binary record.
+type binaryEncoder interface {
+	encodeMapLen(n int) error
+	encodeString(s string) error
+	encodeValue(v any) error
+	bytes() []byte
+}
+
+// binaryDecoder decodes the values of a binary record.
+type binaryDecoder interface {
+	// decodeMapLen returns the length of the next map or false
+	// if the next value is not a map.
+	decodeMapLen() (int, bool, error)
+	decodeValue() (any, error)
+	more() bool
+}
+
+// newBinaryEncoder returns an encoder for the given binary format.
+func newBinaryEncoder(format string) (binaryEncoder, error) {
+	switch format {
+	case FormatCBOR:
+		return &cborEncoder{}, nil
+	case FormatMsgpack:
+		return newMsgpackEncoder(), nil
+	default:
+		return nil, fmt.Errorf("unsupported binary format: %s", format)
+	}
+}
+
+// newBinaryDecoder returns a decoder for the given binary format and data.
+func newBinaryDecoder(format string, data []byte) (binaryDecoder, error) {
+	switch format {
+	case FormatCBOR:
+		return &cborDecoder{data: data}, nil
+	case FormatMsgpack:
+		return newMsgpackDecoder(data), nil
+	default:
+		return nil, fmt.Errorf("unsupported binary format: %s", format)
+	}
+}
+
+// binaryHandler is an slog.Handler that writes length-delimited
+// records in a compact binary format.
+type binaryHandler struct {
+	config Config
+	output io.Writer
+	level  slog.Leveler
+	keys   Keys
+	attrs  attrGroups
+}
+
+var _ (slog.Handler) = (*binaryHandler)(nil)
+
+func newBinaryHandler(config Config, name string, output io.Writer) *binaryHandler {
+	return &binaryHandler{
+		config: config,
+		output: output,
+		level:  configLevel(config),
+		keys:   config.Keys.withDefaults(),
+	}
+}
+
+func (h *binaryHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *binaryHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	other := *h
+	other.attrs = h.attrs.withAttrs(attrs)
+	return &other
+}
+
+func (h *binaryHandler) WithGroup(name string) slog.Handler {
+	other := *h
+	other.attrs = h.attrs.withGroup(name)
+	return &other
+}
+
+func (h *binaryHandler) Handle(ctx context.Context, record slog.Record) error {
+	var attrs []slog.Attr
+	if !record.Time.IsZero() {
+		if value, ok := timeValue(h.config, record.Time.Round(0)); ok {
+			attrs = append(attrs, slog.Attr{Key: h.keys.Time, Value: value})
+		}
+	}
+	attrs = append(attrs, slog.String(h.keys.Level, record.Level.String()))
+	if h.config.EnableSource && record.PC != 0 {
+		frames := runtime.CallersFrames([]uintptr{record.PC})
+		frame, _ := frames.Next()
+		attrs = append(attrs, slog.Group(h.keys.Source,
+			slog.String("function", frame.Function),
+			slog.String("file", frame.File),
+			slog.Int("line", frame.Line),
+		))
+	}
+	attrs = append(attrs, slog.String(h.keys.Message, record.Message))
+	attrs = append(attrs, h.attrs.resolve(record)...)
+
+	enc, err := newBinaryEncoder(h.config.Format)
+	if err != nil {
+		return err
+	}
+	if err := encodeBinaryAttrs(enc, attrs); err != nil {
+		return err
+	}
+	data := enc.bytes()
+	// prefix the record with its length so that
+	// records can be read back from a stream
+	out := binary.AppendUvarint(make([]byte, 0, len(data)+binary.MaxVarintLen64), uint64(len(data)))
+	out = append(out, data...)
+	_, err = h.output.Write(out)
+	return err
+}
+
+// encodeBinaryAttrs encodes the attributes as a map where groups are nested maps.
+func encodeBinaryAttrs(enc binaryEncoder, attrs []slog.Attr) error {
+	if err := enc.encodeMapLen(len(attrs)); err != nil {
+		return err
+	}
+	for _, attr := range attrs {
+		if err := enc.encodeString(attr.Key); err != nil {
+			return err
+		}
+		if attr.Value.Kind() == slog.KindGroup {
+			if err := encodeBinaryAttrs(enc, attr.Value.Group()); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := enc.encodeValue(binaryValue(attr.Value)); err != nil {
+			// fallback to the string representation
+			// of values that cannot be encoded
+			if err := enc.encodeValue(fmt.Sprintf("%+v", attr.Value.Any())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// binaryValue returns the native value to encode for the given slog.Value.
+func binaryValue(value slog.Value) any {
+	switch value.Kind() {
+	case slog.KindBool:
+		return value.Bool()
+	case slog.KindDuration:
+		return value.Duration()
+	case slog.KindFloat64:
+		return value.Float64()
+	case slog.KindInt64:
+		return value.Int64()
+	case slog.KindString:
+		return value.String()
+	case slog.KindTime:
+		return value.Time()
+	case slog.KindUint64:
+		return value.Uint64()
+	}
+	switch v := value.Any().(type) {
+	case error:
+		return v.Error()
+	default:
+		return v
+	}
+}
+
+// binaryInt returns the decoded integer as int64 if it fits so that
+// integers logged as int64 keep their kind.
+func binaryInt(value any) any {
+	if v, ok := value.(uint64); ok && v <= math.MaxInt64 {
+		return int64(v)
+	}
+	return value
+}
+
+// decodeBinaryAttrs decodes a map of attributes where nested maps are groups.
+func decodeBinaryAttrs(dec binaryDecoder) ([]slog.Attr, error) {
+	n, ok, err := dec.decodeMapLen()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("expected map value")
+	}
+	return decodeBinaryMap(dec, n)
+}
+
+// decodeBinaryMap decodes n map entries as attributes.
+func decodeBinaryMap(dec binaryDecoder, n int) ([]slog.Attr, error) {
+	var attrs []slog.Attr
+	for i := 0; i < n; i++ {
+		key, err := dec.decodeValue()
+		if err != nil {
+			return nil, err
+		}
+		size, ok, err := dec.decodeMapLen()
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			group, err := decodeBinaryMap(dec, size)
+			if err != nil {
+				return nil, err
+			}
+			attrs = append(attrs, slog.Attr{Key: fmt.Sprint(key), Value: slog.GroupValue(group...)})
+			continue
+		}
+		value, err := dec.decodeValue()
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, slog.Any(fmt.Sprint(key), value))
+	}
+	return attrs, nil
+}
+
+// Decoder reads records written in one of the binary formats.
+type Decoder struct {
+	reader *bufio.Reader
+	format string
+	keys   Keys
+}
+
+// NewDecoder returns a new Decoder that reads records of the given
+// binary format (FormatCBOR or FormatMsgpack) from r.
+func NewDecoder(r io.Reader, format string) (*Decoder, error) {
+	format = strings.ToLower(format)
+	switch format {
+	case FormatCBOR, FormatMsgpack:
+		return &Decoder{reader: bufio.NewReader(r), format: format, keys: DefaultKeys()}, nil
+	default:
+		return nil, fmt.Errorf("unsupported binary format: %s", format)
+	}
+}
+
+// SetKeys sets the key names used to restore the time,
+// level, and message of records.
+//
+// Records are decoded using DefaultKeys if no keys are set.
+func (d *Decoder) SetKeys(keys Keys) {
+	d.keys = keys.withDefaults()
+}
+
+// Decode reads the next record.
+//
+// The time, level, and message are restored to the record, and all
+// other values, including the logger name, are added as attributes.
+// At the end of the input Decode returns io.EOF.
+func (d *Decoder) Decode() (slog.Record, error) {
+	size, err := binary.ReadUvarint(d.reader)
+	if err != nil {
+		return slog.Record{}, err
+	}
+	if size > maxRecordSize {
+		return slog.Record{}, fmt.Errorf("record size %d exceeds maximum", size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(d.reader, data); err != nil {
+		return slog.Record{}, unexpectedEOF(err)
+	}
+	dec, err := newBinaryDecoder(d.format, data)
+	if err != nil {
+		return slog.Record{}, err
+	}
+	attrs, err := decodeBinaryAttrs(dec)
+	if err != nil {
+		return slog.Record{}, err
+	}
+	if dec.more() {
+		return slog.Record{}, errors.New("unexpected data after record")
+	}
+
+	var record slog.Record
+	var rest []slog.Attr
+	for _, attr := range attrs {
+		switch attr.Key {
+		case d.keys.Time:
+			if attr.Value.Kind() == slog.KindTime {
+				record.Time = attr.Value.Time()
+				continue
+			}
+		case d.keys.Level:
+			if err := record.Level.UnmarshalText([]byte(attr.Value.String())); err == nil {
+				continue
+			}
+		case d.keys.Message:
+			record.Message = attr.Value.String()
+			continue
+		}
+		rest = append(rest, attr)
+	}
+	record = slog.NewRecord(record.Time, record.Level, record.Message, 0)
+	record.AddAttrs(rest...)
+	return record, nil
+}
+
+// Convert reads all binary records of the given format from r and writes
+// them to w using the output format (FormatJSON or FormatText).
+func Convert(w io.Writer, r io.Reader, from string, to string) error {
+	dec, err := NewDecoder(r, from)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	for {
+		record, err := dec.Decode()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var name string
+		record.Attrs(func(attr slog.Attr) bool {
+			if attr.Key == dec.keys.Name {
+				name = attr.Value.String()
+				return false
+			}
+			return true
+		})
+		handler := newHandler(Config{Format: to}, name, w)
+		if err := handler.Handle(ctx, record); err != nil {
+			return err
+		}
+	}
+}
+
+// unexpectedEOF converts io.EOF into io.ErrUnexpectedEOF.
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+type cborEncoder struct {
+	buf []byte
+}
+
+func (e *cborEncoder) encodeMapLen(n int) error {
+	e.buf = appendCBORHead(e.buf, cborMajorMap, uint64(n))
+	return nil
+}
+
+func (e *cborEncoder) encodeString(s string) error {
+	e.buf = appendCBORHead(e.buf, cborMajorText, uint64(len(s)))
+	e.buf = append(e.buf, s...)
+	return nil
+}
+
+func (e *cborEncoder) encodeValue(v any) error {
+	switch value := v.(type) {
+	case time.Duration:
+		v = cbor.Tag{Number: cborTagDuration, Content: int64(value)}
+	case uint64:
+		v = cbor.Tag{Number: cborTagUint64, Content: value}
+	}
+	data, err := cborEncMode.Marshal(v)
+	if err != nil {
+		return err
+	}
+	e.buf = append(e.buf, data...)
+	return nil
+}
+
+func (e *cborEncoder) bytes() []byte {
+	return e.buf
+}
+
+type cborDecoder struct {
+	data []byte
+}
+
+func (d *cborDecoder) decodeMapLen() (int, bool, error) {
+	if len(d.data) == 0 {
+		return 0, false, io.ErrUnexpectedEOF
+	}
+	if d.data[0]>>5 != cborMajorMap {
+		return 0, false, nil
+	}
+	n, rest, err := readCBORHead(d.data)
+	if err != nil {
+		return 0, false, err
+	}
+	if n > uint64(len(rest)) {
+		return 0, false, io.ErrUnexpectedEOF
+	}
+	d.data = rest
+	return int(n), true, nil
+}
+
+func (d *cborDecoder) decodeValue() (any, error) {
+	var value any
+	rest, err := cborDecMode.UnmarshalFirst(d.data, &value)
+	if err != nil {
+		return nil, err
+	}
+	d.data = rest
+	if tag, ok := value.(cbor.Tag); ok {
+		switch tag.Number {
+		case cborTagDuration:
+			if n, ok := binaryInt(tag.Content).(int64); ok {
+				return time.Duration(n), nil
+			}
+		case cborTagUint64:
+			if n, ok := tag.Content.(uint64); ok {
+				return n, nil
+			}
+		}
+	}
+	return binaryInt(value), nil
+}
+
+func (d *cborDecoder) more() bool {
+	return len(d.data) > 0
+}
+
+const (
+	cborMajorText = 3
+	cborMajorMap  = 5
+)
+
+// appendCBORHead appends a CBOR data item head with the given major type and argument.
+func appendCBORHead(buf []byte, major byte, n uint64) []byte {
+	major <<= 5
+	switch {
+	case n < 24:
+		return append(buf, major|byte(n))
+	case n <= 0xff:
+		return append(buf, major|24, byte(n))
+	case n <= 0xffff:
+		return binary.BigEndian.AppendUint16(append(buf, major|25), uint16(n))
+	case n <= 0xffffffff:
+		return binary.BigEndian.AppendUint32(append(buf, major|26), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, major|27), n)
+	}
+}
+
+// readCBORHead reads a CBOR data item head and returns its argument and the remaining data.
+func readCBORHead(data []byte) (uint64, []byte, error) {
+	info := data[0] & 0x1f
+	data = data[1:]
+	var size int
+	switch {
+	case info < 24:
+		return uint64(info), data, nil
+	case info == 24:
+		size = 1
+	case info == 25:
+		size = 2
+	case info == 26:
+		size = 4
+	case info == 27:
+		size = 8
+	default:
+		return 0, nil, fmt.Errorf("unsupported cbor head: %d", info)
+	}
+	if len(data) < size {
+		return 0, nil, io.ErrUnexpectedEOF
+	}
+	var n uint64
+	for _, b := range data[:size] {
+		n = n<<8 | uint64(b)
+	}
+	return n, data[size:], nil
+}
+
+type msgpackEncoder struct {
+	buf bytes.Buffer
+	enc *msgpack.Encoder
+}
+
+func newMsgpackEncoder() *msgpackEncoder {
+	e := &msgpackEncoder{}
+	e.enc = msgpack.NewEncoder(&e.buf)
+	e.enc.UseCompactInts(true)
+	return e
+}
+
+func (e *msgpackEncoder) encodeMapLen(n int) error {
+	return e.enc.EncodeMapLen(n)
+}
+
+func (e *msgpackEncoder) encodeString(s string) error {
+	return e.enc.EncodeString(s)
+}
+
+func (e *msgpackEncoder) encodeValue(v any) error {
+	// encode into a separate buffer so that
+	// a failed value does not corrupt the record
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.UseCompactInts(true)
+	switch value := v.(type) {
+	case time.Duration:
+		if err := encodeMsgpackExt(enc, &buf, msgpackExtDuration, uint64(value)); err != nil {
+			return err
+		}
+	case uint64:
+		if err := encodeMsgpackExt(enc, &buf, msgpackExtUint64, value); err != nil {
+			return err
+		}
+	default:
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+	}
+	_, err := e.buf.Write(buf.Bytes())
+	return err
+}
+
+func (e *msgpackEncoder) bytes() []byte {
+	return e.buf.Bytes()
+}
+
+// encodeMsgpackExt encodes n as an extension value of the given type.
+func encodeMsgpackExt(enc *msgpack.Encoder, buf *bytes.Buffer, ext int8, n uint64) error {
+	if err := enc.EncodeExtHeader(ext, 8); err != nil {
+		return err
+	}
+	_, err := buf.Write(binary.BigEndian.AppendUint64(nil, n))
+	return err
+}
+
+type msgpackDecoder struct {
+	data   []byte
+	reader *bytes.Reader
+	dec    *msgpack.Decoder
+}
+
+func newMsgpackDecoder(data []byte) *msgpackDecoder {
+	reader := bytes.NewReader(data)
+	return &msgpackDecoder{
+		data:   data,
+		reader: reader,
+		dec:    msgpack.NewDecoder(reader),
+	}
+}
+
+func (d *msgpackDecoder) decodeMapLen() (int, bool, error) {
+	code, err := d.dec.PeekCode()
+	if err != nil {
+		return 0, false, unexpectedEOF(err)
+	}
+	if !msgpcode.IsFixedMap(code) && code != msgpcode.Map16 && code != msgpcode.Map32 {
+		return 0, false, nil
+	}
+	n, err := d.dec.DecodeMapLen()
+	if err != nil {
+		return 0, false, unexpectedEOF(err)
+	}
+	return n, true, nil
+}
+
+func (d *msgpackDecoder) decodeValue() (any, error) {
+	if value, ok, err := d.decodeExt(); ok || err != nil {
+		return value, err
+	}
+	value, err := d.dec.DecodeInterfaceLoose()
+	if err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	return binaryInt(value), nil
+}
+
+// decodeExt decodes the next value if it is one of the extension types
+// written by the encoder, or returns false if it is any other value.
+func (d *msgpackDecoder) decodeExt() (any, bool, error) {
+	// the decoder reads directly from the bytes.Reader
+	// so the next value starts at the unread offset
+	offset := len(d.data) - d.reader.Len()
+	if offset+1 >= len(d.data) || d.data[offset] != msgpcode.FixExt8 {
+		return nil, false, nil
+	}
+	ext := int8(d.data[offset+1])
+	if ext != msgpackExtDuration && ext != msgpackExtUint64 {
+		return nil, false, nil
+	}
+	if _, _, err := d.dec.DecodeExtHeader(); err != nil {
+		return nil, true, unexpectedEOF(err)
+	}
+	buf := make([]byte, 8)
+	if err := d.dec.ReadFull(buf); err != nil {
+		return nil, true, unexpectedEOF(err)
+	}
+	n := binary.BigEndian.Uint64(buf)
+	if ext == msgpackExtDuration {
+		return time.Duration(n), true, nil
+	}
+	return n, true, nil
+}
+
+func (d *msgpackDecoder) more() bool {
+	return d.reader.Len() > 0
+}
@@ -0,0 +1,177 @@
+package corelog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBinaryHandlerRoundTrip(t *testing.T) {
+	for _, format := range []string{FormatCBOR, FormatMsgpack} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			handler := newBinaryHandler(Config{Format: format}, "test", &buf)
+
+			now := time.Unix(1700000000, 123456789).UTC()
+			record := slog.NewRecord(now, slog.LevelError, "message", 0)
+			record.AddAttrs(
+				slog.String(nameKey, "test"),
+				slog.Int64("int", -10),
+				slog.Int64("positive", 300),
+				slog.Uint64("uint", 5),
+				slog.Uint64("max", math.MaxUint64),
+				slog.Float64("float", 1.5),
+				slog.Bool("bool", true),
+				slog.Duration("duration", 5*time.Second),
+				slog.Duration("negative", -time.Millisecond),
+				slog.Time("time", now),
+				slog.Group("group", slog.String("key", "value")),
+			)
+			err := handler.WithGroup("").Handle(context.Background(), record)
+			require.NoError(t, err)
+			err = handler.Handle(context.Background(), record)
+			require.NoError(t, err)
+
+			dec, err := NewDecoder(&buf, format)
+			require.NoError(t, err)
+
+			for i := 0; i < 2; i++ {
+				actual, err := dec.Decode()
+				require.NoError(t, err)
+
+				assert.True(t, now.Equal(actual.Time))
+				assert.Equal(t, slog.LevelError, actual.Level)
+				assert.Equal(t, "message", actual.Message)
+
+				attrs := map[string]slog.Value{}
+				actual.Attrs(func(a slog.Attr) bool {
+					attrs[a.Key] = a.Value
+					return true
+				})
+				assert.Equal(t, "test", attrs[nameKey].String())
+				assert.Equal(t, int64(-10), attrs["int"].Int64())
+				assert.Equal(t, slog.KindInt64, attrs["positive"].Kind())
+				assert.Equal(t, int64(300), attrs["positive"].Int64())
+				assert.Equal(t, slog.KindUint64, attrs["uint"].Kind())
+				assert.Equal(t, uint64(5), attrs["uint"].Uint64())
+				assert.Equal(t, slog.KindUint64, attrs["max"].Kind())
+				assert.Equal(t, uint64(math.MaxUint64), attrs["max"].Uint64())
+				assert.Equal(t, slog.KindDuration, attrs["duration"].Kind())
+				assert.Equal(t, 5*time.Second, attrs["duration"].Duration())
+				assert.Equal(t, slog.KindDuration, attrs["negative"].Kind())
+				assert.Equal(t, -time.Millisecond, attrs["negative"].Duration())
+				assert.Equal(t, 1.5, attrs["float"].Float64())
+				assert.Equal(t, true, attrs["bool"].Bool())
+				assert.Equal(t, slog.KindTime, attrs["time"].Kind())
+				assert.True(t, now.Equal(attrs["time"].Time()))
+
+				group := attrs["group"].Group()
+				require.Len(t, group, 1)
+				assert.Equal(t, "key", group[0].Key)
+				assert.Equal(t, "value", group[0].Value.String())
+			}
+
+			_, err = dec.Decode()
+			assert.ErrorIs(t, err, io.EOF)
+		})
+	}
+}
+
+func TestBinaryHandlerWithAttrsAndGroup(t *testing.T) {
+	var buf bytes.Buffer
+	handler := newBinaryHandler(Config{Format: FormatCBOR}, "test", &buf).
+		WithAttrs([]slog.Attr{slog.String("outer", "value")}).
+		WithGroup("group").
+		WithAttrs([]slog.Attr{slog.String("inner", "value")})
+
+	record := slog.NewRecord(time.Time{}, slog.LevelInfo, "message", 0)
+	record.AddAttrs(slog.Any("err", errors.New("test error")))
+	require.NoError(t, handler.Handle(context.Background(), record))
+
+	dec, err := NewDecoder(&buf, FormatCBOR)
+	require.NoError(t, err)
+	actual, err := dec.Decode()
+	require.NoError(t, err)
+
+	expected := []slog.Attr{
+		slog.String("outer", "value"),
+		slog.Group("group",
+			slog.String("inner", "value"),
+			slog.String("err", "test error"),
+		),
+	}
+	assertRecordAttrs(t, actual, expected...)
+}
+
+func TestBinaryFormatWithUpperCaseName(t *testing.T) {
+	var buf bytes.Buffer
+	registry := NewRegistry(Config{Format: "CBOR"})
+	registry.SetOutput(OutputStderr, &buf)
+	registry.NewLogger("test").Info("message")
+
+	dec, err := NewDecoder(&buf, "CBOR")
+	require.NoError(t, err)
+	record, err := dec.Decode()
+	require.NoError(t, err)
+	assert.Equal(t, "message", record.Message)
+}
+
+func TestNewDecoderWithInvalidFormat(t *testing.T) {
+	_, err := NewDecoder(&bytes.Buffer{}, FormatJSON)
+	assert.Error(t, err)
+}
+
+func TestDecoderWithTruncatedRecord(t *testing.T) {
+	var buf bytes.Buffer
+	handler := newBinaryHandler(Config{Format: FormatMsgpack}, "test", &buf)
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, "message", 0)
+	require.NoError(t, handler.Handle(context.Background(), record))
+
+	data := buf.Bytes()[:buf.Len()-1]
+	dec, err := NewDecoder(bytes.NewReader(data), FormatMsgpack)
+	require.NoError(t, err)
+
+	_, err = dec.Decode()
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestConvertToJSON(t *testing.T) {
+	var in bytes.Buffer
+	handler := newBinaryHandler(Config{Format: FormatMsgpack}, "test", &in)
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, "message", 0)
+	record.AddAttrs(slog.String(nameKey, "test"), slog.Int("key", 10))
+	require.NoError(t, handler.Handle(context.Background(), record))
+
+	var out bytes.Buffer
+	err := Convert(&out, &in, FormatMsgpack, FormatJSON)
+	require.NoError(t, err)
+
+	var values map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &values))
+	assert.Equal(t, "INFO", values[levelKey])
+	assert.Equal(t, "message", values[msgKey])
+	assert.Equal(t, "test", values[nameKey])
+	assert.Equal(t, float64(10), values["key"])
+}
+
+func TestConvertToText(t *testing.T) {
+	var in bytes.Buffer
+	handler := newBinaryHandler(Config{Format: FormatCBOR}, "test", &in)
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, "message", 0)
+	record.AddAttrs(slog.String(nameKey, "test"), slog.Int("key", 10))
+	require.NoError(t, handler.Handle(context.Background(), record))
+
+	var out bytes.Buffer
+	err := Convert(&out, &in, FormatCBOR, FormatText)
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "test message key=10")
+}
@@ -0,0 +1,206 @@
+package corelog
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+const (
+	// EventCodeKey is the key for the event code attribute that is
+	// used as the signature ID of CEF records.
+	EventCodeKey = "event_code"
+	// defaultCEFVendor is the default CEF device vendor.
+	defaultCEFVendor = "Source Network"
+	// defaultCEFVersion is the default CEF device version.
+	defaultCEFVersion = "1.0"
+	// defaultCEFSignature is the CEF signature ID for records without an event code.
+	defaultCEFSignature = "log"
+)
+
+// CEFConfig contains the CEF header values.
+type CEFConfig struct {
+	// Vendor is the device vendor.
+	Vendor string
+	// Product is the device product, which defaults to the logger name.
+	Product string
+	// Version is the device version.
+	Version string
+}
+
+// EventCode returns an slog.Attr for an event code.
+//
+// The event code is used as the signature ID of CEF records.
+func EventCode(code string) slog.Attr {
+	return slog.String(EventCodeKey, code)
+}
+
+// cefHandler is an slog.Handler that writes records in the
+// Common Event Format (CEF) used by SIEM systems.
+type cefHandler struct {
+	config Config
+	output io.Writer
+	level  slog.Leveler
+	keys   Keys
+	header string
+	attrs  attrGroups
+}
+
+var _ (slog.Handler) = (*cefHandler)(nil)
+
+func newCEFHandler(config Config, name string, output io.Writer) *cefHandler {
+	vendor := config.CEF.Vendor
+	if vendor == "" {
+		vendor = defaultCEFVendor
+	}
+	product := config.CEF.Product
+	if product == "" {
+		product = name
+	}
+	version := config.CEF.Version
+	if version == "" {
+		version = defaultCEFVersion
+	}
+	header := "CEF:0|" + escapeCEFHeader(vendor) + "|" + escapeCEFHeader(product) + "|" + escapeCEFHeader(version) + "|"
+	return &cefHandler{
+		config: config,
+		output: output,
+		level:  configLevel(config),
+		keys:   config.Keys.withDefaults(),
+		header: header,
+	}
+}
+
+func (h *cefHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *cefHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	other := *h
+	other.attrs = h.attrs.withAttrs(attrs)
+	return &other
+}
+
+func (h *cefHandler) WithGroup(name string) slog.Handler {
+	other := *h
+	other.attrs = h.attrs.withGroup(name)
+	return &other
+}
+
+func (h *cefHandler) Handle(ctx context.Context, record slog.Record) error {
+	signature := defaultCEFSignature
+	var extension bytes.Buffer
+
+	if !record.Time.IsZero() {
+		// default to unix milliseconds if no value is set
+		value, ok := timeValue(h.config, record.Time.Round(0))
+		if ok && value.Kind() == slog.KindTime {
+			value = slog.Int64Value(value.Time().UnixMilli())
+		}
+		if ok {
+			appendCEFExtension(&extension, "rt", value.String())
+		}
+	}
+
+	// the event code and logger name are
+	// mapped to the CEF header and fields
+	attrs := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		switch attr.Key {
+		case EventCodeKey:
+			signature = attr.Value.String()
+		case h.keys.Name:
+			appendCEFExtension(&extension, "cat", attr.Value.String())
+		case h.keys.Error:
+			appendCEFExtension(&extension, "reason", attr.Value.String())
+		default:
+			attrs.AddAttrs(attr)
+		}
+		return true
+	})
+	h.appendAttrs(&extension, "", h.attrs.resolve(attrs))
+
+	var buf bytes.Buffer
+	buf.WriteString(h.header)
+	buf.WriteString(escapeCEFHeader(signature))
+	buf.WriteByte('|')
+	buf.WriteString(escapeCEFHeader(record.Message))
+	buf.WriteByte('|')
+	buf.WriteString(strconv.Itoa(cefSeverity(record.Level)))
+	buf.WriteByte('|')
+	buf.Write(extension.Bytes())
+	buf.WriteByte('\n')
+
+	_, err := h.output.Write(buf.Bytes())
+	return err
+}
+
+// appendAttrs writes the attributes as extension key value pairs,
+// where group keys are joined with ".".
+func (h *cefHandler) appendAttrs(buf *bytes.Buffer, prefix string, attrs []slog.Attr) {
+	for _, attr := range attrs {
+		if attr.Value.Kind() == slog.KindGroup {
+			h.appendAttrs(buf, prefix+attr.Key+".", attr.Value.Group())
+			continue
+		}
+		appendCEFExtension(buf, prefix+attr.Key, attr.Value.String())
+	}
+}
+
+// appendCEFExtension writes an extension key value pair.
+//
+// Characters that are not valid in extension keys are removed
+// and pairs with empty keys are skipped.
+func appendCEFExtension(buf *bytes.Buffer, key string, value string) {
+	key = strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '.' {
+			return r
+		}
+		return -1
+	}, key)
+	if key == "" {
+		return
+	}
+	if buf.Len() > 0 {
+		buf.WriteByte(' ')
+	}
+	buf.WriteString(key)
+	buf.WriteByte('=')
+	buf.WriteString(escapeCEFExtension(value))
+}
+
+// cefHeaderEscaper escapes CEF header values.
+var cefHeaderEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\r\n", " ", "\n", " ", "\r", " ")
+
+// cefExtensionEscaper escapes CEF extension values.
+var cefExtensionEscaper = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r\n", `\n`, "\n", `\n`, "\r", `\r`)
+
+// escapeCEFHeader escapes backslashes and pipes in header values
+// and replaces line breaks with spaces.
+func escapeCEFHeader(value string) string {
+	return cefHeaderEscaper.Replace(value)
+}
+
+// escapeCEFExtension escapes backslashes, equals signs, and line breaks in extension values.
+func escapeCEFExtension(value string) string {
+	return cefExtensionEscaper.Replace(value)
+}
+
+// cefSeverity returns the CEF severity from 0 to 10 for the level.
+func cefSeverity(level slog.Level) int {
+	switch {
+	case level < slog.LevelInfo:
+		return 1
+	case level < slog.LevelWarn:
+		return 3
+	case level < slog.LevelError:
+		return 6
+	case level < slog.LevelError+4:
+		return 8
+	default:
+		return 10
+	}
+}
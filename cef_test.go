@@ -0,0 +1,54 @@
+package corelog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCEFHandler(t *testing.T) {
+	var buf bytes.Buffer
+	config := Config{CEF: CEFConfig{Vendor: "Acme|Corp", Product: "node", Version: "2.0"}}
+	handler := newCEFHandler(config, "test", &buf).WithGroup("req")
+
+	now := time.Now()
+	record := slog.NewRecord(now, slog.LevelError, "login failed", 0)
+	record.AddAttrs(
+		slog.String(nameKey, "test"),
+		EventCode("auth-100"),
+		slog.String(errorKey, "bad password"),
+		slog.String("user", "a=b\\c"),
+		slog.String("note", "line1\nline2"),
+	)
+	require.NoError(t, handler.Handle(context.Background(), record))
+
+	expected := "CEF:0|Acme\\|Corp|node|2.0|auth-100|login failed|8|" +
+		"rt=" + strconv.FormatInt(now.UnixMilli(), 10) +
+		" cat=test reason=bad password req.user=a\\=b\\\\c req.note=line1\\nline2\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestCEFHandlerWithDefaults(t *testing.T) {
+	var buf bytes.Buffer
+	handler := newCEFHandler(Config{TimeFormat: TimeFormatNone}, "test", &buf)
+
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, "msg|with pipe", 0)
+	record.AddAttrs(slog.Int("$count", 1))
+	require.NoError(t, handler.Handle(context.Background(), record))
+
+	assert.Equal(t, "CEF:0|Source Network|test|1.0|log|msg\\|with pipe|3|count=1\n", buf.String())
+}
+
+func TestCEFSeverity(t *testing.T) {
+	assert.Equal(t, 1, cefSeverity(slog.LevelDebug))
+	assert.Equal(t, 3, cefSeverity(slog.LevelInfo))
+	assert.Equal(t, 6, cefSeverity(slog.LevelWarn))
+	assert.Equal(t, 8, cefSeverity(slog.LevelError))
+	assert.Equal(t, 10, cefSeverity(slog.LevelError+4))
+}
@@ -0,0 +1,252 @@
+package corelog
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+const (
+	ansiReset = "\033[0m"
+)
+
+// colorDepth is the number of colors supported by an output.
+type colorDepth int
+
+const (
+	// colorNone specifies that color is disabled.
+	colorNone colorDepth = iota
+	// color16 specifies the 16 basic ANSI colors.
+	color16
+	// color256 specifies the 256 color xterm palette.
+	color256
+	// colorTrue specifies 24-bit true color.
+	colorTrue
+)
+
+// ansiColors contains the ANSI codes for all supported color names.
+var ansiColors = map[string]string{
+	"bold":           "1",
+	"faint":          "2",
+	"italic":         "3",
+	"underline":      "4",
+	"black":          "30",
+	"red":            "31",
+	"green":          "32",
+	"yellow":         "33",
+	"blue":           "34",
+	"magenta":        "35",
+	"cyan":           "36",
+	"white":          "37",
+	"gray":           "90",
+	"bright-red":     "91",
+	"bright-green":   "92",
+	"bright-yellow":  "93",
+	"bright-blue":    "94",
+	"bright-magenta": "95",
+	"bright-cyan":    "96",
+	"bright-white":   "97",
+}
+
+// basicColors contains the xterm RGB values of the 16 basic colors
+// in the order of the 256 color palette.
+var basicColors = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// cubeLevels contains the RGB component values of the 6x6x6 color cube.
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// colorize wraps the text in the ANSI codes for the given color.
+//
+// Colors can be a name, a 256 color palette index, or a "#rrggbb" hex
+// value, and multiple colors can be combined with "+", e.g. "bold+208".
+// Colors are converted to the closest color supported by the color depth
+// and unknown colors are ignored.
+func colorize(text string, color string, depth colorDepth) string {
+	if depth == colorNone {
+		return text
+	}
+	var codes []string
+	for _, name := range strings.Split(color, "+") {
+		if code, ok := colorCode(strings.ToLower(strings.TrimSpace(name)), depth); ok {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return text
+	}
+	return "\033[" + strings.Join(codes, ";") + "m" + text + ansiReset
+}
+
+// colorCode returns the ANSI code for the given color and color depth.
+func colorCode(color string, depth colorDepth) (string, bool) {
+	if code, ok := ansiColors[color]; ok {
+		return code, true
+	}
+	if hex, ok := strings.CutPrefix(color, "#"); ok {
+		value, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil || len(hex) != 6 {
+			return "", false
+		}
+		rgb := [3]uint8{uint8(value >> 16), uint8(value >> 8), uint8(value)}
+		switch depth {
+		case colorTrue:
+			return "38;2;" + strconv.Itoa(int(rgb[0])) + ";" + strconv.Itoa(int(rgb[1])) + ";" + strconv.Itoa(int(rgb[2])), true
+		case color256:
+			return "38;5;" + strconv.Itoa(rgbToIndex(rgb)), true
+		default:
+			return basicColorCode(rgb), true
+		}
+	}
+	index, err := strconv.ParseUint(color, 10, 8)
+	if err != nil {
+		return "", false
+	}
+	if depth == color16 {
+		return basicColorCode(indexToRGB(int(index))), true
+	}
+	return "38;5;" + strconv.Itoa(int(index)), true
+}
+
+// rgbToIndex returns the closest 256 color palette index for the RGB value.
+func rgbToIndex(rgb [3]uint8) int {
+	cube := 16
+	for i, scale := range []int{36, 6, 1} {
+		// find the closest cube level for the component
+		level := 0
+		for j := range cubeLevels {
+			if absDiff(rgb[i], cubeLevels[j]) < absDiff(rgb[i], cubeLevels[level]) {
+				level = j
+			}
+		}
+		cube += scale * level
+	}
+	if rgb[0] != rgb[1] || rgb[1] != rgb[2] {
+		return cube
+	}
+	// use the grayscale ramp for gray values
+	// if it is closer than the color cube
+	gray := min(23, max(0, (int(rgb[0])-3)/10))
+	if colorDistance(rgb, indexToRGB(232+gray)) < colorDistance(rgb, indexToRGB(cube)) {
+		return 232 + gray
+	}
+	return cube
+}
+
+// indexToRGB returns the RGB value of the 256 color palette index.
+func indexToRGB(index int) [3]uint8 {
+	switch {
+	case index < 16:
+		return basicColors[index]
+	case index < 232:
+		index -= 16
+		return [3]uint8{cubeLevels[index/36], cubeLevels[index/6%6], cubeLevels[index%6]}
+	default:
+		level := uint8(8 + 10*(index-232))
+		return [3]uint8{level, level, level}
+	}
+}
+
+// basicColorCode returns the ANSI code of the closest basic color to the RGB value.
+func basicColorCode(rgb [3]uint8) string {
+	closest := 0
+	for i := range basicColors {
+		if colorDistance(rgb, basicColors[i]) < colorDistance(rgb, basicColors[closest]) {
+			closest = i
+		}
+	}
+	if closest < 8 {
+		return strconv.Itoa(30 + closest)
+	}
+	return strconv.Itoa(90 + closest - 8)
+}
+
+// absDiff returns the absolute difference between two color components.
+func absDiff(a, b uint8) int {
+	if a > b {
+		return int(a - b)
+	}
+	return int(b - a)
+}
+
+// colorDistance returns the squared distance between two RGB values.
+func colorDistance(a, b [3]uint8) int {
+	var distance int
+	for i := range a {
+		d := int(a[i]) - int(b[i])
+		distance += d * d
+	}
+	return distance
+}
+
+// detectColor returns the color depth to use for the given output.
+//
+// Color is disabled by the config, enabled by FORCE_COLOR or CLICOLOR_FORCE,
+// disabled by NO_COLOR, CLICOLOR=0, or TERM=dumb, and otherwise enabled
+// if the output is a terminal. The depth is derived from FORCE_COLOR,
+// COLORTERM, and TERM.
+func detectColor(config Config, output io.Writer) colorDepth {
+	if config.DisableColor {
+		return colorNone
+	}
+	if depth, ok := forceColorDepth(os.Getenv("FORCE_COLOR")); ok {
+		if depth == colorNone {
+			return colorNone
+		}
+		return max(depth, terminalColorDepth())
+	}
+	if force := os.Getenv("CLICOLOR_FORCE"); force != "" && force != "0" {
+		return terminalColorDepth()
+	}
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("CLICOLOR") == "0" {
+		return colorNone
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return colorNone
+	}
+	if locked, ok := output.(*lockedWriter); ok {
+		output = locked.writer
+	}
+	file, ok := output.(*os.File)
+	if !ok || !term.IsTerminal(int(file.Fd())) {
+		return colorNone
+	}
+	return terminalColorDepth()
+}
+
+// forceColorDepth returns the color depth for the given FORCE_COLOR
+// value, or false if the value is not set.
+func forceColorDepth(value string) (colorDepth, bool) {
+	switch strings.ToLower(value) {
+	case "":
+		return colorNone, false
+	case "0", "false":
+		return colorNone, true
+	case "2":
+		return color256, true
+	case "3":
+		return colorTrue, true
+	default:
+		return color16, true
+	}
+}
+
+// terminalColorDepth returns the color depth of the terminal
+// from the COLORTERM and TERM environment variables.
+func terminalColorDepth() colorDepth {
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return colorTrue
+	}
+	if strings.Contains(os.Getenv("TERM"), "256color") {
+		return color256
+	}
+	return color16
+}
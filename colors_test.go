@@ -0,0 +1,70 @@
+package corelog
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColorize(t *testing.T) {
+	assert.Equal(t, "\033[31mtext\033[0m", colorize("text", "red", color16))
+	assert.Equal(t, "\033[1;92mtext\033[0m", colorize("text", "bold+bright-green", color16))
+	assert.Equal(t, "text", colorize("text", "invalid", color16))
+	assert.Equal(t, "text", colorize("text", "red", colorNone))
+}
+
+func TestColorizeWithDepth(t *testing.T) {
+	assert.Equal(t, "\033[38;2;255;135;0mtext\033[0m", colorize("text", "#ff8700", colorTrue))
+	assert.Equal(t, "\033[38;5;208mtext\033[0m", colorize("text", "#ff8700", color256))
+	assert.Equal(t, "\033[33mtext\033[0m", colorize("text", "#ff8700", color16))
+	assert.Equal(t, "\033[38;5;208mtext\033[0m", colorize("text", "208", colorTrue))
+	assert.Equal(t, "\033[91mtext\033[0m", colorize("text", "196", color16))
+	assert.Equal(t, "text", colorize("text", "#ff", colorTrue))
+	assert.Equal(t, "text", colorize("text", "256", colorTrue))
+}
+
+func TestRGBToIndex(t *testing.T) {
+	assert.Equal(t, 16, rgbToIndex([3]uint8{0, 0, 0}))
+	assert.Equal(t, 231, rgbToIndex([3]uint8{255, 255, 255}))
+	assert.Equal(t, 196, rgbToIndex([3]uint8{255, 0, 0}))
+	assert.Equal(t, 244, rgbToIndex([3]uint8{128, 128, 128}))
+}
+
+func TestDetectColorWithNonTerminal(t *testing.T) {
+	t.Setenv("FORCE_COLOR", "")
+	t.Setenv("CLICOLOR_FORCE", "")
+	assert.Equal(t, colorNone, detectColor(Config{}, &bytes.Buffer{}))
+}
+
+func TestDetectColorWithForceColor(t *testing.T) {
+	t.Setenv("COLORTERM", "")
+	t.Setenv("TERM", "xterm")
+	t.Setenv("NO_COLOR", "1")
+
+	t.Setenv("FORCE_COLOR", "1")
+	assert.Equal(t, color16, detectColor(Config{}, &bytes.Buffer{}))
+
+	t.Setenv("FORCE_COLOR", "2")
+	assert.Equal(t, color256, detectColor(Config{}, &bytes.Buffer{}))
+
+	t.Setenv("FORCE_COLOR", "3")
+	assert.Equal(t, colorTrue, detectColor(Config{}, &bytes.Buffer{}))
+
+	t.Setenv("FORCE_COLOR", "0")
+	assert.Equal(t, colorNone, detectColor(Config{}, &bytes.Buffer{}))
+
+	t.Setenv("FORCE_COLOR", "1")
+	assert.Equal(t, colorNone, detectColor(Config{DisableColor: true}, &bytes.Buffer{}))
+}
+
+func TestDetectColorWithCLIColorForce(t *testing.T) {
+	t.Setenv("FORCE_COLOR", "")
+	t.Setenv("CLICOLOR_FORCE", "1")
+	t.Setenv("COLORTERM", "truecolor")
+	assert.Equal(t, colorTrue, detectColor(Config{}, &bytes.Buffer{}))
+
+	t.Setenv("COLORTERM", "")
+	t.Setenv("TERM", "xterm-256color")
+	assert.Equal(t, color256, detectColor(Config{}, &bytes.Buffer{}))
+}
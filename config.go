@@ -1,36 +1,48 @@
 package corelog
 
 import (
-	"os"
+	"errors"
+	"fmt"
+	"maps"
+	"path"
+	"reflect"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
-	"sync"
 )
 
 const (
+	// LevelDebug specifies debug log level.
+	LevelDebug = "debug"
 	// LevelDebug specifies info log level.
 	LevelInfo = "info"
+	// LevelWarn specifies warn log level.
+	LevelWarn = "warn"
 	// LevelDebug specifies error log level.
 	LevelError = "error"
 	// FormatText specifies text output for a logger.
 	FormatText = "text"
 	// FormatJSON specifies json output for a logger.
 	FormatJSON = "json"
+	// FormatCBOR specifies length-delimited cbor output for a logger.
+	FormatCBOR = "cbor"
+	// FormatMsgpack specifies length-delimited msgpack output for a logger.
+	FormatMsgpack = "msgpack"
+	// FormatTemplate specifies user template output for a logger.
+	FormatTemplate = "template"
+	// FormatPretty specifies human-friendly multi-line output for a logger.
+	FormatPretty = "pretty"
+	// FormatCEF specifies Common Event Format output for a logger.
+	FormatCEF = "cef"
 	// OutputStdout specifies stdout output for a logger.
 	OutputStdout = "stdout"
 	// OutputStderr specifies stderr output for a logger.
 	OutputStderr = "stderr"
 )
 
-var (
-	configMutex     sync.RWMutex
-	configValue     Config
-	configOverrides = make(map[string]Config)
-)
-
 func init() {
-	SetConfig(DefaultConfig())
-	SetConfigOverrides(os.Getenv("LOG_OVERRIDES"))
+	loadEnvConfig()
 }
 
 // Config contains general settings for a logger.
@@ -47,91 +59,804 @@ type Config struct {
 	Output string
 	// DisableColor specifies if colored output is disabled.
 	DisableColor bool
+	// Template specifies the text/template layout used by the template format.
+	Template string
+	// Keys specifies the attribute key names used by structured formats.
+	Keys Keys
+	// TimeFormat specifies the timestamp format or a custom time layout.
+	TimeFormat string
+	// TimeZone specifies the time zone of timestamps.
+	TimeZone string
+	// Theme specifies the color theme of text output.
+	Theme string
+	// EnableNameColor enables stable per logger name colors in text output.
+	EnableNameColor bool
+	// CEF specifies the header values used by the cef format.
+	CEF CEFConfig
+	// Attrs specifies static attributes that are added to every record.
+	Attrs map[string]string
 }
 
 // DefaultConfig returns a config with default values.
 //
-// The default values are derived from environment variables.
+// The default values are derived from environment variables,
+// see SetEnvPrefix for their names.
 func DefaultConfig() Config {
-	enableSource, _ := strconv.ParseBool(os.Getenv("LOG_SOURCE"))
-	enableStacktrace, _ := strconv.ParseBool(os.Getenv("LOG_STACKTRACE"))
-	disableColor, _ := strconv.ParseBool(os.Getenv("LOG_NO_COLOR"))
-
-	return Config{
-		Level:            strings.ToLower(os.Getenv("LOG_LEVEL")),
-		Output:           strings.ToLower(os.Getenv("LOG_OUTPUT")),
-		Format:           strings.ToLower(os.Getenv("LOG_FORMAT")),
-		EnableSource:     enableSource,
-		EnableStackTrace: enableStacktrace,
-		DisableColor:     disableColor,
+	var config Config
+	for _, field := range configFields {
+		if field.env == "" {
+			continue
+		}
+		if _, val := getEnv(field.env); val != "" {
+			// invalid values are reported by CheckConfig
+			setConfigField(&config, field, val)
+		}
+	}
+	if _, text := getEnv(envAttrs); text != "" {
+		// invalid values are reported by CheckConfig
+		config.Attrs, _ = parseAttrs(text, config.Attrs)
 	}
+	return config
 }
 
-// GetConfig returns the config for a named logger.
+// GetConfig returns the config for a named logger of the default registry.
 func GetConfig(name string) Config {
-	configMutex.RLock()
-	defer configMutex.RUnlock()
+	return defaultRegistry.GetConfig(name)
+}
+
+// GetConfig returns the config for a named logger.
+//
+// Logger names are a dot separated hierarchy, and the config is merged from the
+// current config values for all loggers, the overrides with patterns that match
+// the name in the order they were set, and the overrides of the name and its
+// parents, so that the most specific literal name wins. Fields that are not set
+// in an override inherit their value from the previous config, e.g. an override
+// for "net" also applies to "net.p2p".
+//
+// Literal names always win over patterns, including the names of parents, so
+// an override for "net" wins over an override for "net.*" for "net.p2p".
+func (r *Registry) GetConfig(name string) Config {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.state.get(name).clone()
+}
 
-	if val, ok := configOverrides[name]; ok {
-		return val
+// configState contains the config for all loggers and the config overrides.
+type configState struct {
+	config    Config
+	overrides map[string]ConfigOverride
+	// patterns contains the overrides with name
+	// patterns in the order they were set
+	patterns []overridePattern
+}
+
+// clone returns a copy of the config state.
+func (s configState) clone() configState {
+	overrides := maps.Clone(s.overrides)
+	if overrides == nil {
+		overrides = make(map[string]ConfigOverride)
+	}
+	return configState{
+		config:    s.config,
+		overrides: overrides,
+		patterns:  slices.Clone(s.patterns),
 	}
-	return configValue
 }
 
-// SetConfig sets the config values for all loggers.
+// get returns the config for a named logger.
+func (s configState) get(name string) Config {
+	config := s.config
+	for _, pattern := range s.patterns {
+		if pattern.match(name) {
+			config = mergeConfig(config, s.overrides[pattern.name])
+		}
+	}
+	for _, parent := range nameHierarchy(name) {
+		if override, ok := s.overrides[parent]; ok {
+			config = mergeConfig(config, override)
+		}
+	}
+	return config
+}
+
+// nameHierarchy returns the names of all parents of the
+// given logger name followed by the name itself.
+func nameHierarchy(name string) []string {
+	var names []string
+	for i := range name {
+		if name[i] == '.' && i > 0 {
+			names = append(names, name[:i])
+		}
+	}
+	if name != "" {
+		names = append(names, name)
+	}
+	return names
+}
+
+// overridePattern is a config override name pattern.
+type overridePattern struct {
+	name  string
+	match func(name string) bool
+}
+
+// checkOverrideName returns an error if the override name is
+// a glob pattern or regular expression that is not valid.
+func checkOverrideName(name string) error {
+	var err error
+	if expr, ok := strings.CutPrefix(name, "re:"); ok {
+		_, err = regexp.Compile(expr)
+	} else if strings.ContainsAny(name, "*?[") {
+		_, err = path.Match(name, "")
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPattern, err)
+	}
+	return nil
+}
+
+// newOverridePattern returns the pattern for the override name,
+// or false if the name is not a glob or regular expression.
+//
+// Invalid patterns do not match any names.
+func newOverridePattern(name string) (overridePattern, bool) {
+	if expr, ok := strings.CutPrefix(name, "re:"); ok {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return overridePattern{name: name, match: func(string) bool { return false }}, true
+		}
+		return overridePattern{name: name, match: re.MatchString}, true
+	}
+	if strings.ContainsAny(name, "*?[") {
+		return overridePattern{
+			name: name,
+			match: func(value string) bool {
+				ok, _ := path.Match(name, value)
+				return ok
+			},
+		}, true
+	}
+	return overridePattern{}, false
+}
+
+// mergeConfig returns the base config with the fields that are set in the override.
+func mergeConfig(base Config, override ConfigOverride) Config {
+	for _, field := range allConfigFields(&override.Config) {
+		if field.copy != nil && slices.Contains(override.Fields, field.key) {
+			field.copy(&base, &override.Config)
+		}
+	}
+	return base
+}
+
+// SetConfig sets the config values for all loggers of the default registry.
 func SetConfig(cfg Config) {
-	configMutex.Lock()
-	defer configMutex.Unlock()
-	configValue = cfg
+	defaultRegistry.SetConfig(cfg)
 }
 
-// SetConfigOverride sets the config override for the given named logger.
+// SetConfig sets the config values for all loggers.
+func (r *Registry) SetConfig(cfg Config) {
+	cfg = cfg.clone()
+	r.change(func() error {
+		r.state.config = cfg
+		return nil
+	})
+}
+
+// SetConfigOverride sets the config override for the
+// given named logger of the default registry.
 func SetConfigOverride(name string, cfg Config) {
-	configMutex.Lock()
-	defer configMutex.Unlock()
-	configOverrides[name] = cfg
+	defaultRegistry.SetConfigOverride(name, cfg)
+}
+
+// SetConfigOverride sets the config override for the given named logger.
+//
+// All fields of the config are set, including fields with zero values.
+// Use SetPartialConfigOverride to only set some of the fields.
+//
+// The name can be a glob pattern containing "*", "?", or "[" or a regular
+// expression prefixed with "re:", e.g. "net.*" or "re:^db\..*$", that sets
+// the override for all logger names that match the pattern.
+func (r *Registry) SetConfigOverride(name string, cfg Config) {
+	var fields []string
+	for _, field := range allConfigFields(&cfg) {
+		if field.copy != nil {
+			fields = append(fields, field.key)
+		}
+	}
+	r.SetPartialConfigOverride(ConfigOverride{Name: name, Config: cfg, Fields: fields})
+}
+
+// SetPartialConfigOverride sets the config override
+// of the default registry for the override name.
+func SetPartialConfigOverride(override ConfigOverride) {
+	defaultRegistry.SetPartialConfigOverride(override)
+}
+
+// SetPartialConfigOverride sets the config override for the override name.
+//
+// Only the fields with keys in override.Fields are set, e.g. "level" or
+// "attr.service", and all other fields inherit their values from the config
+// for all loggers and the overrides of parent loggers.
+func (r *Registry) SetPartialConfigOverride(override ConfigOverride) {
+	override.Config = override.Config.clone()
+	override.Fields = slices.Clone(override.Fields)
+	r.change(func() error {
+		r.state.setOverride(override)
+		return nil
+	})
+}
+
+// setOverride sets the config override while the registry lock is held.
+func (s *configState) setOverride(override ConfigOverride) {
+	if _, ok := s.overrides[override.Name]; !ok {
+		if pattern, ok := newOverridePattern(override.Name); ok {
+			s.patterns = append(s.patterns, pattern)
+		}
+	}
+	s.overrides[override.Name] = override
+}
+
+// RemoveConfigOverride removes the config override for the given
+// logger name or name pattern of the default registry.
+func RemoveConfigOverride(name string) {
+	defaultRegistry.RemoveConfigOverride(name)
+}
+
+// RemoveConfigOverride removes the config override for the given logger name
+// or name pattern, so that the logger inherits its config from its parents.
+func (r *Registry) RemoveConfigOverride(name string) {
+	r.change(func() error {
+		r.state.removeOverride(name)
+		return nil
+	})
+}
+
+// ClearConfigOverrides removes all config overrides of the default registry.
+func ClearConfigOverrides() {
+	defaultRegistry.ClearConfigOverrides()
+}
+
+// ClearConfigOverrides removes all config overrides.
+func (r *Registry) ClearConfigOverrides() {
+	r.change(func() error {
+		r.state.clearOverrides()
+		return nil
+	})
+}
+
+// ConfigSnapshot is a copy of the config and all config overrides.
+type ConfigSnapshot struct {
+	state configState
+}
+
+// Snapshot returns a copy of the config and all config overrides of the default registry.
+func Snapshot() ConfigSnapshot {
+	return defaultRegistry.Snapshot()
+}
+
+// Snapshot returns a copy of the config and all config overrides,
+// which can be restored with Restore, e.g. at the end of a test.
+func (r *Registry) Snapshot() ConfigSnapshot {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return ConfigSnapshot{state: r.state.clone()}
+}
+
+// Restore replaces the config and all config overrides of the default registry with the snapshot.
+func Restore(snapshot ConfigSnapshot) {
+	defaultRegistry.Restore(snapshot)
+}
+
+// Restore replaces the config and all config overrides with the snapshot.
+//
+// A snapshot can be restored more than once and to other registries.
+func (r *Registry) Restore(snapshot ConfigSnapshot) {
+	r.change(func() error {
+		r.state = snapshot.state.clone()
+		return nil
+	})
+}
+
+// removeOverride removes the config override for the given
+// name or name pattern while the registry lock is held.
+func (s *configState) removeOverride(name string) {
+	delete(s.overrides, name)
+	s.patterns = slices.DeleteFunc(s.patterns, func(pattern overridePattern) bool {
+		return pattern.name == name
+	})
+}
+
+// clearOverrides removes all config overrides while the registry lock is held.
+func (s *configState) clearOverrides() {
+	s.overrides = make(map[string]ConfigOverride)
+	s.patterns = nil
+}
+
+// ConfigOverride is a config override for a named logger.
+type ConfigOverride struct {
+	// Name is the logger name or name pattern.
+	Name string
+	// Config is the config for the named logger.
+	Config Config
+	// Fields contains the keys of the config fields that are set by the
+	// override. All other fields inherit their values from the parent config.
+	Fields []string
+}
+
+// values returns the values of the fields that are set by the override.
+func (o ConfigOverride) values() map[string]any {
+	values := make(map[string]any)
+	for _, field := range allConfigFields(&o.Config) {
+		if field.get != nil && slices.Contains(o.Fields, field.key) {
+			values[field.key] = field.get(&o.Config)
+		}
+	}
+	return values
+}
+
+// SetConfigOverrides parses and sets config overrides of the default registry from the given text.
+func SetConfigOverrides(text string) {
+	defaultRegistry.SetConfigOverrides(text)
 }
 
 // SetConfigOverrides parses and sets config overrides from the given text.
 //
 // Overrides are separated by ";", and override values are comma separated,
 // where the first value is the name, and the remaining values are key value
-// pairs separated by "=".
-func SetConfigOverrides(text string) {
+// pairs separated by "=". Names and values that contain "," or ";" can be
+// double quoted using Go syntax, e.g. template="{{.Level}}; {{.Msg}}".
+//
+// Invalid overrides, keys, and values are ignored.
+// Use ParseConfigOverrides to report them.
+func (r *Registry) SetConfigOverrides(text string) {
+	overrides, _ := ParseConfigOverrides(text)
+
+	r.change(func() error {
+		for _, override := range overrides {
+			r.state.setOverride(override)
+		}
+		return nil
+	})
+}
+
+// ParseConfigOverrides parses config overrides from the given text
+// using the same syntax as SetConfigOverrides.
+//
+// The overrides are returned in the order they are defined and invalid
+// keys and values are skipped. The returned error contains a *ConfigError
+// with the column of the invalid text for every invalid override, key, and value.
+func ParseConfigOverrides(text string) ([]ConfigOverride, error) {
+	return parseConfigOverrides(text, "")
+}
+
+// parseConfigOverrides parses config overrides from the given text and
+// sets the source of returned errors.
+func parseConfigOverrides(text string, source string) ([]ConfigOverride, error) {
+	var overrides []ConfigOverride
+	var errs []error
+	// offset is the position of the current part in the text
+	offset := 0
 	// overrides are separated by ";"
-	for _, override := range strings.Split(text, ";") {
-		// override parts are separated by ","
-		parts := strings.Split(override, ",")
+	for _, item := range splitUnquoted(text, ';') {
 		// first part is the override name
-		name := strings.TrimSpace(parts[0])
-		if name == "" {
+		part := splitUnquoted(item, ',')[0]
+		pairs := strings.TrimPrefix(item[len(part):], ",")
+		if strings.TrimSpace(part) == "" {
+			if strings.TrimSpace(item) != "" {
+				errs = append(errs, &ConfigError{Source: source, Column: offset + 1, Err: ErrMissingName})
+			}
+			offset += len(item) + 1
 			continue // empty logger name
 		}
-		config := DefaultConfig()
-		// remaining parts are key value pairs
-		for _, pair := range parts[1:] {
-			// key value pairs are separated by "="
-			values := strings.SplitN(pair, "=", 2)
-			if len(values) != 2 {
-				continue // invalid key value
-			}
-			key := strings.TrimSpace(values[0])
-			val := strings.TrimSpace(values[1])
-			switch strings.ToLower(key) {
-			case "level":
-				config.Level = strings.ToLower(val)
-			case "format":
-				config.Format = strings.ToLower(val)
-			case "output":
-				config.Output = strings.ToLower(val)
-			case "stacktrace":
-				config.EnableStackTrace, _ = strconv.ParseBool(val)
-			case "source":
-				config.EnableSource, _ = strconv.ParseBool(val)
-			case "no-color":
-				config.DisableColor, _ = strconv.ParseBool(val)
-			}
+		name, err := unquoteValue(part)
+		if err == nil {
+			err = checkOverrideName(name)
+		}
+		if err != nil {
+			column := offset + len(part) - len(strings.TrimLeft(part, " \t")) + 1
+			errs = append(errs, &ConfigError{Source: source, Column: column, Name: name, Err: err})
+			offset += len(item) + 1
+			continue
+		}
+		override := ConfigOverride{Name: name}
+		errs = append(errs, parseConfigPairs(&override, pairs, source, offset+len(part)+1)...)
+		offset += len(item) + 1
+		overrides = append(overrides, override)
+	}
+	return overrides, errors.Join(errs...)
+}
+
+// parseConfigPairs sets the fields of the override from the comma separated
+// key value pairs in the text, where offset is the position of the text in
+// the config overrides text.
+func parseConfigPairs(override *ConfigOverride, text, source string, offset int) []error {
+	var errs []error
+	for _, pair := range splitUnquoted(text, ',') {
+		column := offset + len(pair) - len(strings.TrimLeft(pair, " \t")) + 1
+		offset += len(pair) + 1
+		if strings.TrimSpace(pair) == "" {
+			continue // empty pair
+		}
+		// key value pairs are separated by "="
+		key, val, ok := strings.Cut(pair, "=")
+		if !ok {
+			errs = append(errs, &ConfigError{
+				Source: source,
+				Column: column,
+				Name:   override.Name,
+				Err:    fmt.Errorf("%w: %q", ErrInvalidPair, strings.TrimSpace(pair)),
+			})
+			continue
+		}
+		key = strings.TrimSpace(key)
+		field, ok := lookupConfigField(key)
+		if !ok {
+			errs = append(errs, &ConfigError{Source: source, Column: column, Name: override.Name, Key: key, Err: ErrUnknownKey})
+			continue
+		}
+		val, err := unquoteValue(val)
+		if err == nil {
+			err = setConfigField(&override.Config, field, val)
+		}
+		if err != nil {
+			errs = append(errs, &ConfigError{Source: source, Column: column, Name: override.Name, Key: key, Err: err})
+			continue
+		}
+		override.Fields = appendField(override.Fields, field)
+	}
+	return errs
+}
+
+// splitUnquoted splits the text at every separator that is not in a quoted
+// value. Quoted values start with a double quote after a separator or "=".
+func splitUnquoted(text string, sep byte) []string {
+	var parts []string
+	start, quoted := 0, false
+	// last is the last character that is not a space
+	var last byte = sep
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quoted && c == '\\':
+			i++ // skip the escaped character
+		case quoted && c == '"':
+			quoted = false
+		case c == '"' && (last == sep || last == ',' || last == ';' || last == '='):
+			quoted = true
+		case !quoted && c == sep:
+			parts = append(parts, text[start:i])
+			start = i + 1
+		}
+		if c != ' ' && c != '\t' {
+			last = c
+		}
+	}
+	return append(parts, text[start:])
+}
+
+// unquoteValue returns the value without surrounding spaces,
+// and without quotes if it is a quoted value.
+func unquoteValue(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, `"`) {
+		return value, nil
+	}
+	unquoted, err := strconv.Unquote(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid quoted value %s", value)
+	}
+	return unquoted, nil
+}
+
+// quoteValue returns the value quoted if it cannot
+// be parsed from config overrides as it is.
+func quoteValue(value string) string {
+	if strings.ContainsAny(value, `,;"`) || strings.TrimSpace(value) != value {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+// formatConfigPairs returns the fields of the config with the
+// given keys as comma separated key value pairs.
+func formatConfigPairs(config Config, keys []string) string {
+	var pairs []string
+	for _, field := range allConfigFields(&config) {
+		if field.get != nil && slices.Contains(keys, field.key) {
+			pairs = append(pairs, field.key+"="+quoteValue(fmt.Sprint(field.get(&config))))
+		}
+	}
+	return strings.Join(pairs, ",")
+}
+
+// String returns the fields of the config that are not empty as
+// comma separated key value pairs using the syntax of config
+// overrides, e.g. "level=debug,format=json".
+func (c Config) String() string {
+	var keys []string
+	for _, field := range allConfigFields(&c) {
+		if field.get != nil && !reflect.ValueOf(field.get(&c)).IsZero() {
+			keys = append(keys, field.key)
 		}
-		SetConfigOverride(name, config)
+	}
+	return formatConfigPairs(c, keys)
+}
+
+// MarshalText returns the config in the format of String.
+func (c Config) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText sets the config fields from comma separated key value
+// pairs using the syntax of config overrides.
+//
+// Fields that are not set in the text keep their current values.
+func (c *Config) UnmarshalText(text []byte) error {
+	override := ConfigOverride{Config: *c}
+	if err := errors.Join(parseConfigPairs(&override, string(text), "", 0)...); err != nil {
+		return err
+	}
+	*c = override.Config
+	return nil
+}
+
+// String returns the override using the syntax of config overrides,
+// e.g. "net,level=debug,source=false".
+func (o ConfigOverride) String() string {
+	name := quoteValue(o.Name)
+	if pairs := formatConfigPairs(o.Config, o.Fields); pairs != "" {
+		return name + "," + pairs
+	}
+	return name
+}
+
+// configField is a config field that can be set from text.
+type configField struct {
+	// key is the name of the field in overrides and config files.
+	key string
+	// env is the name of the environment variable for
+	// the field without the prefix, see SetEnvPrefix.
+	env string
+	// usage is the usage text of the flag for the field,
+	// see BindFlags.
+	usage string
+	// set parses the value and sets the field on the config.
+	set func(config *Config, value string) error
+	// get returns the value of the field, or is nil if the
+	// field only sets other fields.
+	get func(config *Config) any
+	// copy sets the field from the source config, or is
+	// nil if the field only sets other fields.
+	copy func(dst, src *Config)
+	// fields contains the keys of the other fields
+	// that are set by the field.
+	fields []string
+	// check returns an error if the value of the field is
+	// not valid, or is nil if all values are valid.
+	check func(config *Config) error
+	// registered returns an error if the value of the field is not
+	// registered, or is nil if the field has no registry. It is not
+	// checked when the value is set, since formats and themes can
+	// be registered after the config is parsed.
+	registered func(config *Config) error
+}
+
+// configFields contains all config fields that can be set from text.
+var configFields = []configField{
+	lowerField("level", "LEVEL", "sets the logging level (debug, info, warn, error)", func(c *Config) *string { return &c.Level }).withCheck(checkLevel),
+	lowerField("format", "FORMAT", "sets the logging format (json, text, pretty, cef, cbor, msgpack, template)", func(c *Config) *string { return &c.Format }).withRegistered(checkFormat),
+	lowerField("output", "OUTPUT", "sets the output path (stderr, stdout)", func(c *Config) *string { return &c.Output }).withCheck(checkOutput),
+	boolField("stacktrace", "STACKTRACE", "enables stacktraces", func(c *Config) *bool { return &c.EnableStackTrace }),
+	boolField("source", "SOURCE", "enables source location", func(c *Config) *bool { return &c.EnableSource }),
+	boolField("no-color", "NO_COLOR", "disables color text output", func(c *Config) *bool { return &c.DisableColor }),
+	stringField("template", "TEMPLATE", "sets the template layout", func(c *Config) *string { return &c.Template }).withCheck(checkTemplate),
+	stringField("time-format", "TIME_FORMAT", "sets the timestamp format", func(c *Config) *string { return &c.TimeFormat }),
+	stringField("time-zone", "TIME_ZONE", "sets the timestamp time zone", func(c *Config) *string { return &c.TimeZone }).withCheck(checkTimeZone),
+	lowerField("theme", "THEME", "sets the color theme", func(c *Config) *string { return &c.Theme }).withRegistered(checkTheme),
+	boolField("name-color", "NAME_COLOR", "enables per logger name colors", func(c *Config) *bool { return &c.EnableNameColor }),
+	stringField("cef.vendor", "CEF_VENDOR", "sets the cef device vendor", func(c *Config) *string { return &c.CEF.Vendor }),
+	stringField("cef.product", "CEF_PRODUCT", "sets the cef device product", func(c *Config) *string { return &c.CEF.Product }),
+	stringField("cef.version", "CEF_VERSION", "sets the cef device version", func(c *Config) *string { return &c.CEF.Version }),
+	{
+		key:   "keys",
+		env:   "KEYS",
+		usage: "sets attribute key names",
+		set: func(c *Config, value string) (err error) {
+			c.Keys, err = parseKeys(value, c.Keys)
+			return err
+		},
+		fields: []string{"key.time", "key.level", "key.msg", "key.source", "key.name", "key.err", "key.stack"},
+	},
+	stringField("key.time", "", "sets the time attribute key", func(c *Config) *string { return &c.Keys.Time }),
+	stringField("key.level", "", "sets the level attribute key", func(c *Config) *string { return &c.Keys.Level }),
+	stringField("key.msg", "", "sets the message attribute key", func(c *Config) *string { return &c.Keys.Message }),
+	stringField("key.source", "", "sets the source attribute key", func(c *Config) *string { return &c.Keys.Source }),
+	stringField("key.name", "", "sets the logger name attribute key", func(c *Config) *string { return &c.Keys.Name }),
+	stringField("key.err", "", "sets the error attribute key", func(c *Config) *string { return &c.Keys.Error }),
+	stringField("key.stack", "", "sets the stacktrace attribute key", func(c *Config) *string { return &c.Keys.Stack }),
+}
+
+// withCheck returns the field with a check of its string value.
+func (f configField) withCheck(check func(value string) error) configField {
+	get := f.get
+	f.check = func(c *Config) error {
+		return check(get(c).(string))
+	}
+	return f
+}
+
+// withRegistered returns the field with a registry check of its string value.
+func (f configField) withRegistered(check func(value string) error) configField {
+	get := f.get
+	f.registered = func(c *Config) error {
+		return check(get(c).(string))
+	}
+	return f
+}
+
+// appendField appends the keys that are set by the field
+// to the keys if they are not included.
+func appendField(keys []string, field configField) []string {
+	if field.copy != nil && !slices.Contains(keys, field.key) {
+		keys = append(keys, field.key)
+	}
+	for _, key := range field.fields {
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// lookupConfigField returns the config field with the given key.
+func lookupConfigField(key string) (configField, bool) {
+	// attribute names are case sensitive
+	if len(key) > len(attrPrefix) && strings.EqualFold(key[:len(attrPrefix)], attrPrefix) {
+		return attrField(key[len(attrPrefix):]), true
+	}
+	key = strings.ToLower(key)
+	for _, field := range configFields {
+		if field.key == key {
+			return field, true
+		}
+	}
+	return configField{}, false
+}
+
+// attrPrefix is the prefix of the keys of static attribute fields.
+const attrPrefix = "attr."
+
+// attrField returns a config field for the static attribute with the given name.
+//
+// The attributes of a config are copied before they are changed,
+// as they are shared by all copies of the config.
+func attrField(name string) configField {
+	return configField{
+		key: attrPrefix + name,
+		set: func(c *Config, value string) error {
+			c.Attrs = setAttr(c.Attrs, name, value)
+			return nil
+		},
+		get: func(c *Config) any { return c.Attrs[name] },
+		copy: func(dst, src *Config) {
+			dst.Attrs = setAttr(dst.Attrs, name, src.Attrs[name])
+		},
+	}
+}
+
+// clone returns a copy of the config that does not share the attributes
+// with the config, so that the attributes of configs that are set or
+// returned by a registry cannot be changed outside of the registry.
+func (c Config) clone() Config {
+	c.Attrs = maps.Clone(c.Attrs)
+	return c
+}
+
+// parseAttrs parses static attributes from text with the syntax
+// "name=value,name=value" and sets them on a copy of the attributes.
+func parseAttrs(text string, attrs map[string]string) (map[string]string, error) {
+	var errs []error
+	for _, pair := range splitUnquoted(text, ',') {
+		if strings.TrimSpace(pair) == "" {
+			continue // empty pair
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			errs = append(errs, fmt.Errorf("%w: %q", ErrInvalidPair, strings.TrimSpace(pair)))
+			continue
+		}
+		value, err := unquoteValue(value)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		attrs = setAttr(attrs, name, value)
+	}
+	return attrs, errors.Join(errs...)
+}
+
+// setAttr returns a copy of the attributes with the given attribute set.
+func setAttr(attrs map[string]string, name, value string) map[string]string {
+	attrs = maps.Clone(attrs)
+	if attrs == nil {
+		attrs = make(map[string]string)
+	}
+	attrs[name] = value
+	return attrs
+}
+
+// allConfigFields returns all config fields followed by
+// the fields of the static attributes of the configs.
+func allConfigFields(configs ...*Config) []configField {
+	names := make(map[string]struct{})
+	for _, config := range configs {
+		for name := range config.Attrs {
+			names[name] = struct{}{}
+		}
+	}
+	if len(names) == 0 {
+		return configFields
+	}
+	fields := slices.Clip(configFields)
+	for _, name := range sortedKeys(names) {
+		fields = append(fields, attrField(name))
+	}
+	return fields
+}
+
+// stringField returns a config field for a string value.
+func stringField(key, env, usage string, field func(*Config) *string) configField {
+	return configField{
+		key:   key,
+		env:   env,
+		usage: usage,
+		set: func(c *Config, value string) error {
+			*field(c) = value
+			return nil
+		},
+		get: func(c *Config) any { return *field(c) },
+		copy: func(dst, src *Config) {
+			*field(dst) = *field(src)
+		},
+	}
+}
+
+// lowerField returns a config field for a case insensitive string value.
+func lowerField(key, env, usage string, field func(*Config) *string) configField {
+	return configField{
+		key:   key,
+		env:   env,
+		usage: usage,
+		set: func(c *Config, value string) error {
+			*field(c) = strings.ToLower(value)
+			return nil
+		},
+		get: func(c *Config) any { return *field(c) },
+		copy: func(dst, src *Config) {
+			*field(dst) = *field(src)
+		},
+	}
+}
+
+// boolField returns a config field for a boolean value.
+func boolField(key, env, usage string, field func(*Config) *bool) configField {
+	return configField{
+		key:   key,
+		env:   env,
+		usage: usage,
+		set: func(c *Config, value string) error {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("invalid boolean %q", value)
+			}
+			*field(c) = enabled
+			return nil
+		},
+		get: func(c *Config) any { return *field(c) },
+		copy: func(dst, src *Config) {
+			*field(dst) = *field(src)
+		},
 	}
 }
@@ -0,0 +1,179 @@
+package corelog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// overridesKey is the config file key that contains the config overrides.
+const overridesKey = "overrides"
+
+// errExpectedMap is returned for config file values that must be maps.
+var errExpectedMap = errors.New("expected map")
+
+// LoadConfigFile loads the config and config overrides of the
+// default registry from the file at the given path.
+func LoadConfigFile(path string) error {
+	return defaultRegistry.LoadConfigFile(path)
+}
+
+// LoadConfigFile loads the config and config overrides from the file at the given path.
+//
+// The file format is detected from the file extension and can be YAML (.yaml, .yml),
+// JSON (.json), or TOML (.toml). Top level keys set the config values for all loggers
+// and the overrides key contains a map of logger names to config values. Keys are the
+// same as in LOG_OVERRIDES and nested maps are joined with ".", so that the TOML table
+// [cef] with vendor = "Acme" sets cef.vendor.
+//
+// The global config starts from DefaultConfig and overrides only set the fields
+// that are in the file, so that all other fields inherit their values from the
+// global config. The loaded values replace the config and all existing config
+// overrides.
+func (r *Registry) LoadConfigFile(path string) error {
+	config, overrides, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+	r.replaceConfig(config, overrides)
+	return nil
+}
+
+// readConfigFile reads and parses the config and config
+// overrides from the file at the given path.
+func readConfigFile(path string) (Config, map[string]ConfigOverride, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, nil, err
+	}
+	return parseConfigFile(path, data)
+}
+
+// replaceConfig replaces the config and all config overrides,
+// followed by the given ordered overrides.
+func (r *Registry) replaceConfig(config Config, overrides map[string]ConfigOverride, ordered ...ConfigOverride) {
+	r.change(func() error {
+		r.state.config = config
+		r.state.clearOverrides()
+		// patterns are set in sorted order as
+		// the file order of maps is not known
+		for _, name := range sortedKeys(overrides) {
+			r.state.setOverride(overrides[name])
+		}
+		for _, override := range ordered {
+			r.state.setOverride(override)
+		}
+		return nil
+	})
+}
+
+// parseConfigFile parses the config and config overrides from the
+// file contents using the format of the file extension.
+func parseConfigFile(path string, data []byte) (Config, map[string]ConfigOverride, error) {
+	values := make(map[string]any)
+	var err error
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	case ".json":
+		err = json.Unmarshal(data, &values)
+	case ".toml":
+		err = toml.Unmarshal(data, &values)
+	default:
+		return Config{}, nil, fmt.Errorf("unsupported config file extension: %s", ext)
+	}
+	if err != nil {
+		return Config{}, nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	var errs []error
+	overrideValues, ok := values[overridesKey].(map[string]any)
+	if values[overridesKey] != nil && !ok {
+		errs = append(errs, &ConfigError{Source: path, Key: overridesKey, Err: errExpectedMap})
+	}
+	delete(values, overridesKey)
+
+	config := DefaultConfig()
+	errs = append(errs, setConfigValues(&config, nil, path, "", "", values)...)
+
+	overrides := make(map[string]ConfigOverride)
+	for _, name := range sortedKeys(overrideValues) {
+		key := overridesKey + "." + name
+		if err := checkOverrideName(name); err != nil {
+			errs = append(errs, &ConfigError{Source: path, Name: name, Err: err})
+			continue
+		}
+		fields, ok := overrideValues[name].(map[string]any)
+		if !ok {
+			errs = append(errs, &ConfigError{Source: path, Key: key, Err: errExpectedMap})
+			continue
+		}
+		override := ConfigOverride{Name: name}
+		errs = append(errs, setConfigValues(&override.Config, &override.Fields, path, "", key+".", fields)...)
+		overrides[name] = override
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return Config{}, nil, err
+	}
+	return config, overrides, nil
+}
+
+// setConfigValues sets the config fields from the values and returns
+// an error for every unknown key and invalid value.
+//
+// The keys of the set fields are appended to the fields if it is not nil.
+// The source is set on returned errors, the prefix is prepended to the
+// keys of nested maps, and the path is prepended to the keys in errors.
+func setConfigValues(config *Config, fields *[]string, source string, prefix string, path string, values map[string]any) []error {
+	var errs []error
+	for _, key := range sortedKeys(values) {
+		if nested, ok := values[key].(map[string]any); ok {
+			errs = append(errs, setConfigValues(config, fields, source, prefix+key+".", path+key+".", nested)...)
+			continue
+		}
+		field, ok := lookupConfigField(prefix + key)
+		if !ok {
+			errs = append(errs, &ConfigError{Source: source, Key: path + key, Err: ErrUnknownKey})
+			continue
+		}
+		var text string
+		switch value := values[key].(type) {
+		case string:
+			text = value
+		case bool:
+			text = strconv.FormatBool(value)
+		case int, int64, uint64, float64:
+			text = fmt.Sprint(value)
+		default:
+			errs = append(errs, &ConfigError{Source: source, Key: path + key, Err: fmt.Errorf("invalid value %v", value)})
+			continue
+		}
+		if err := setConfigField(config, field, text); err != nil {
+			errs = append(errs, &ConfigError{Source: source, Key: path + key, Err: err})
+			continue
+		}
+		if fields != nil {
+			*fields = appendField(*fields, field)
+		}
+	}
+	return errs
+}
+
+// sortedKeys returns the keys of the map in sorted order.
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
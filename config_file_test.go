@@ -0,0 +1,162 @@
+package corelog
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConfigFileYAML(t *testing.T) {
+	data := `
+level: error
+format: json
+source: true
+keys: slog
+cef:
+  vendor: Acme
+  version: 2
+overrides:
+  net:
+    level: info
+    key:
+      name: component
+`
+	config, overrides, err := parseConfigFile("config.yaml", []byte(data))
+	require.NoError(t, err)
+
+	assert.Equal(t, LevelError, config.Level)
+	assert.Equal(t, FormatJSON, config.Format)
+	assert.Equal(t, true, config.EnableSource)
+	assert.Equal(t, SlogKeys(), config.Keys)
+	assert.Equal(t, CEFConfig{Vendor: "Acme", Version: "2"}, config.CEF)
+
+	require.Contains(t, overrides, "net")
+	assert.Equal(t, []string{"key.name", "level"}, overrides["net"].Fields)
+
+	net := mergeConfig(config, overrides["net"])
+	assert.Equal(t, LevelInfo, net.Level)
+	assert.Equal(t, FormatJSON, net.Format)
+	assert.Equal(t, "component", net.Keys.Name)
+	assert.Equal(t, slog.TimeKey, net.Keys.Time)
+}
+
+func TestParseConfigFileJSON(t *testing.T) {
+	data := `{
+		"level": "error",
+		"no-color": true,
+		"overrides": {
+			"net.p2p": {"format": "pretty", "time-format": "unix"}
+		}
+	}`
+	config, overrides, err := parseConfigFile("config.json", []byte(data))
+	require.NoError(t, err)
+
+	assert.Equal(t, LevelError, config.Level)
+	assert.Equal(t, true, config.DisableColor)
+
+	require.Contains(t, overrides, "net.p2p")
+	p2p := mergeConfig(config, overrides["net.p2p"])
+	assert.Equal(t, LevelError, p2p.Level)
+	assert.Equal(t, FormatPretty, p2p.Format)
+	assert.Equal(t, TimeFormatUnix, p2p.TimeFormat)
+}
+
+func TestParseConfigFileTOML(t *testing.T) {
+	data := `
+level = "error"
+stacktrace = true
+
+[overrides.net]
+output = "stdout"
+theme = "Light"
+`
+	config, overrides, err := parseConfigFile("config.toml", []byte(data))
+	require.NoError(t, err)
+
+	assert.Equal(t, LevelError, config.Level)
+	assert.Equal(t, true, config.EnableStackTrace)
+
+	require.Contains(t, overrides, "net")
+	net := mergeConfig(config, overrides["net"])
+	assert.Equal(t, OutputStdout, net.Output)
+	assert.Equal(t, ThemeLight, net.Theme)
+	assert.Equal(t, true, net.EnableStackTrace)
+}
+
+func TestParseConfigFileWithUnknownKeys(t *testing.T) {
+	data := `
+levle: error
+overrides:
+  net:
+    cef:
+      vendr: Acme
+`
+	_, _, err := parseConfigFile("config.yml", []byte(data))
+	require.Error(t, err)
+	assert.ErrorContains(t, err, `config.yml: key "levle": unknown config key`)
+	assert.ErrorContains(t, err, `config.yml: key "overrides.net.cef.vendr": unknown config key`)
+}
+
+func TestParseConfigFileWithInvalidValues(t *testing.T) {
+	data := `{"source": "maybe", "level": ["error"], "overrides": {"net": "error"}}`
+	_, _, err := parseConfigFile("config.json", []byte(data))
+	require.Error(t, err)
+	assert.ErrorContains(t, err, `config.json: key "source": invalid boolean "maybe"`)
+	assert.ErrorContains(t, err, `config.json: key "level": invalid value [error]`)
+	assert.ErrorContains(t, err, `config.json: key "overrides.net": expected map`)
+}
+
+func TestParseConfigFileWithInvalidPattern(t *testing.T) {
+	data := `{"overrides": {"re:(": {"level": "debug"}}}`
+	_, _, err := parseConfigFile("config.json", []byte(data))
+	require.Error(t, err)
+
+	var configErr *ConfigError
+	require.ErrorAs(t, err, &configErr)
+	assert.Equal(t, "config.json", configErr.Source)
+	assert.Equal(t, "re:(", configErr.Name)
+	assert.ErrorIs(t, err, ErrInvalidPattern)
+}
+
+func TestParseConfigFileWithUnsupportedExtension(t *testing.T) {
+	_, _, err := parseConfigFile("config.ini", []byte("level=error"))
+	assert.ErrorContains(t, err, "unsupported config file extension: .ini")
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	restoreConfig(t)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte("level: error\noverrides:\n  file:\n    format: json\n  files.*:\n    level: debug\n"), 0o644)
+	require.NoError(t, err)
+
+	require.NoError(t, LoadConfigFile(path))
+	assert.Equal(t, LevelError, GetConfig("").Level)
+	assert.Equal(t, LevelError, GetConfig("file").Level)
+	assert.Equal(t, FormatJSON, GetConfig("file").Format)
+	assert.Equal(t, LevelDebug, GetConfig("files.child").Level)
+}
+
+func TestLoadConfigFileWithMissingFile(t *testing.T) {
+	err := LoadConfigFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+// restoreConfig restores the config and config overrides when the test ends.
+func restoreConfig(t *testing.T) {
+	snapshot := Snapshot()
+	t.Cleanup(func() {
+		Restore(snapshot)
+	})
+}
+
+// resetConfig sets the config and removes all config overrides until the test ends.
+func resetConfig(t *testing.T, config Config) {
+	restoreConfig(t)
+	SetConfig(config)
+	ClearConfigOverrides()
+}
@@ -1,21 +1,24 @@
 package corelog
 
 import (
-	"os"
+	"errors"
+	"log/slog"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDefaultConfigWithEnv(t *testing.T) {
-	os.Setenv("LOG_LEVEL", LevelError)
-	os.Setenv("LOG_OUTPUT", OutputStdout)
-	os.Setenv("LOG_FORMAT", FormatJSON)
-	os.Setenv("LOG_SOURCE", "true")
-	os.Setenv("LOG_STACKTRACE", "true")
-	os.Setenv("LOG_NO_COLOR", "true")
-	t.Cleanup(os.Clearenv)
+	t.Setenv("LOG_LEVEL", LevelError)
+	t.Setenv("LOG_OUTPUT", OutputStdout)
+	t.Setenv("LOG_FORMAT", FormatJSON)
+	t.Setenv("LOG_SOURCE", "true")
+	t.Setenv("LOG_STACKTRACE", "true")
+	t.Setenv("LOG_NO_COLOR", "true")
+	t.Setenv("LOG_TEMPLATE", "{{.Msg}}")
+	t.Setenv("LOG_KEYS", "time=ts,level=severity")
 
 	cfg := DefaultConfig()
 	assert.Equal(t, LevelError, cfg.Level)
@@ -24,13 +27,16 @@ func TestDefaultConfigWithEnv(t *testing.T) {
 	assert.Equal(t, true, cfg.EnableStackTrace)
 	assert.Equal(t, true, cfg.EnableSource)
 	assert.Equal(t, true, cfg.DisableColor)
+	assert.Equal(t, "{{.Msg}}", cfg.Template)
+	assert.Equal(t, Keys{Time: "ts", Level: "severity"}, cfg.Keys)
 }
 
 func TestSetConfigOverrides(t *testing.T) {
 	overrides := []string{
-		"net,level=error,source=true,format=json,invalid",
-		"core,output=stdout,stacktrace=true,no-color=true",
+		"net,level=error,source=true,format=json,invalid,keys=slog,key.name=logger",
+		"core,output=stdout,stacktrace=true,no-color=true,template={{.Msg}},time-format=unix,time-zone=UTC,theme=Light,name-color=true,cef.vendor=Acme,cef.product=node,cef.version=2",
 	}
+	resetConfig(t, Config{})
 	SetConfigOverrides(strings.Join(overrides, ";"))
 
 	cfg := GetConfig("")
@@ -48,6 +54,8 @@ func TestSetConfigOverrides(t *testing.T) {
 	assert.Equal(t, false, net.EnableStackTrace)
 	assert.Equal(t, true, net.EnableSource)
 	assert.Equal(t, false, net.DisableColor)
+	assert.Equal(t, slog.TimeKey, net.Keys.Time)
+	assert.Equal(t, "logger", net.Keys.Name)
 
 	core := GetConfig("core")
 	assert.Equal(t, "", core.Level)
@@ -56,4 +64,331 @@ func TestSetConfigOverrides(t *testing.T) {
 	assert.Equal(t, true, core.EnableStackTrace)
 	assert.Equal(t, false, core.EnableSource)
 	assert.Equal(t, true, core.DisableColor)
+	assert.Equal(t, "{{.Msg}}", core.Template)
+	assert.Equal(t, TimeFormatUnix, core.TimeFormat)
+	assert.Equal(t, "UTC", core.TimeZone)
+	assert.Equal(t, ThemeLight, core.Theme)
+	assert.Equal(t, true, core.EnableNameColor)
+	assert.Equal(t, CEFConfig{Vendor: "Acme", Product: "node", Version: "2"}, core.CEF)
+}
+
+func TestGetConfigWithHierarchy(t *testing.T) {
+	restoreConfig(t)
+	SetConfig(Config{Level: LevelError, Format: FormatJSON})
+	SetConfigOverrides("tree,level=info,source=true;tree.p2p,output=stdout;tree.p2p.dht,level=debug")
+
+	assert.Equal(t, Config{Level: LevelInfo, Format: FormatJSON, EnableSource: true}, GetConfig("tree"))
+	assert.Equal(t, Config{Level: LevelInfo, Format: FormatJSON, EnableSource: true, Output: OutputStdout}, GetConfig("tree.p2p"))
+	assert.Equal(t, Config{Level: LevelDebug, Format: FormatJSON, EnableSource: true, Output: OutputStdout}, GetConfig("tree.p2p.dht"))
+	assert.Equal(t, Config{Level: LevelInfo, Format: FormatJSON, EnableSource: true}, GetConfig("tree.pubsub"))
+	assert.Equal(t, Config{Level: LevelError, Format: FormatJSON}, GetConfig("trees"))
+}
+
+func TestNameHierarchy(t *testing.T) {
+	assert.Equal(t, []string{"net", "net.p2p", "net.p2p.dht"}, nameHierarchy("net.p2p.dht"))
+	assert.Equal(t, []string{"net"}, nameHierarchy("net"))
+	assert.Equal(t, []string{".net"}, nameHierarchy(".net"))
+	assert.Empty(t, nameHierarchy(""))
+}
+
+func TestGetConfigWithPatterns(t *testing.T) {
+	restoreConfig(t)
+	SetConfig(Config{Level: LevelError})
+	SetConfigOverrides(`glob.*,level=info;*store*,format=json;re:^regex\.(a|b)$,output=stdout;glob.p2p,source=true;glob.?2p,level=debug`)
+
+	assert.Equal(t, LevelInfo, GetConfig("glob.pubsub").Level)
+	assert.Equal(t, LevelDebug, GetConfig("glob.p2p").Level)
+	assert.Equal(t, true, GetConfig("glob.p2p").EnableSource)
+	assert.Equal(t, LevelError, GetConfig("glob").Level)
+	assert.Equal(t, FormatJSON, GetConfig("glob.blockstore").Format)
+	assert.Equal(t, LevelInfo, GetConfig("glob.blockstore").Level)
+	assert.Equal(t, OutputStdout, GetConfig("regex.a").Output)
+	assert.Equal(t, "", GetConfig("regex.c").Output)
+}
+
+func TestGetConfigWithPatternPrecedence(t *testing.T) {
+	restoreConfig(t)
+	SetConfig(Config{})
+	SetConfigOverrides("order,format=text;ord*,level=info,format=json;re:^order$,level=debug")
+
+	// patterns are applied in the order they were set
+	// followed by the literal name
+	config := GetConfig("order")
+	assert.Equal(t, LevelDebug, config.Level)
+	assert.Equal(t, FormatText, config.Format)
+
+	// replacing a pattern keeps its position
+	SetConfigOverrides("ord*,level=warn")
+	assert.Equal(t, LevelDebug, GetConfig("order").Level)
+}
+
+func TestGetConfigWithLiteralParentOverPattern(t *testing.T) {
+	registry := NewRegistry(Config{})
+	registry.SetConfigOverrides("net.*,level=debug,format=json;net,level=info")
+
+	// the literal parent wins over the more specific pattern
+	config := registry.GetConfig("net.p2p")
+	assert.Equal(t, LevelInfo, config.Level)
+	assert.Equal(t, FormatJSON, config.Format)
+}
+
+func TestParseConfigOverridesWithInvalidPatterns(t *testing.T) {
+	text := `net,level=info;re:(,level=debug; [,level=debug`
+	overrides, err := ParseConfigOverrides(text)
+	require.Len(t, overrides, 1)
+	assert.Equal(t, "net", overrides[0].Name)
+
+	var errs []*ConfigError
+	for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var configErr *ConfigError
+		require.True(t, errors.As(err, &configErr))
+		errs = append(errs, configErr)
+	}
+	require.Len(t, errs, 2)
+	assert.Equal(t, 16, errs[0].Column)
+	assert.Equal(t, "re:(", errs[0].Name)
+	assert.ErrorIs(t, errs[0], ErrInvalidPattern)
+	assert.Equal(t, 34, errs[1].Column)
+	assert.Equal(t, "[", errs[1].Name)
+	assert.ErrorIs(t, errs[1], ErrInvalidPattern)
+	assert.Equal(t, "re:(", text[errs[0].Column-1:errs[0].Column+3])
+	assert.Equal(t, "[", text[errs[1].Column-1:errs[1].Column])
+}
+
+func TestGetConfigWithInvalidPatterns(t *testing.T) {
+	restoreConfig(t)
+	SetConfig(Config{})
+	SetConfigOverride("re:(", Config{Level: LevelDebug})
+	SetConfigOverride("[", Config{Level: LevelDebug})
+
+	// invalid patterns that are set directly do not match any names
+	assert.Equal(t, "", GetConfig("x").Level)
+	assert.Equal(t, "", GetConfig("[x").Level)
+}
+
+func TestSetConfigOverridesWithPartialConfig(t *testing.T) {
+	restoreConfig(t)
+	SetConfigOverrides("partial,level=debug,source=false")
+	SetConfig(Config{Level: LevelError, Format: FormatJSON, EnableSource: true})
+
+	// fields that are not set by the override
+	// inherit the current global values
+	config := GetConfig("partial")
+	assert.Equal(t, LevelDebug, config.Level)
+	assert.Equal(t, FormatJSON, config.Format)
+	assert.Equal(t, false, config.EnableSource)
+
+	SetConfig(Config{Format: FormatPretty})
+	assert.Equal(t, FormatPretty, GetConfig("partial").Format)
+}
+
+func TestSetConfigOverrideWithZeroValues(t *testing.T) {
+	restoreConfig(t)
+	SetConfig(Config{Level: LevelError, EnableSource: true, Keys: Keys{Name: "logger"}})
+	SetConfigOverride("zero", Config{Format: FormatJSON, Keys: Keys{Time: "ts"}})
+
+	// all fields are set including zero values
+	config := GetConfig("zero")
+	assert.Equal(t, "", config.Level)
+	assert.Equal(t, FormatJSON, config.Format)
+	assert.Equal(t, false, config.EnableSource)
+	assert.Equal(t, Keys{Time: "ts"}, config.Keys)
+}
+
+func TestSetPartialConfigOverride(t *testing.T) {
+	restoreConfig(t)
+	SetConfig(Config{Level: LevelError, EnableSource: true, Keys: Keys{Name: "logger"}})
+	SetPartialConfigOverride(ConfigOverride{
+		Name:   "partial.api",
+		Config: Config{Format: FormatJSON, EnableSource: true, Keys: Keys{Time: "ts"}},
+		Fields: []string{"format", "key.time"},
+	})
+
+	// fields that are not listed inherit their values
+	config := GetConfig("partial.api")
+	assert.Equal(t, LevelError, config.Level)
+	assert.Equal(t, FormatJSON, config.Format)
+	assert.Equal(t, true, config.EnableSource)
+	assert.Equal(t, Keys{Time: "ts", Name: "logger"}, config.Keys)
+}
+
+func TestSetConfigOverridesWithKeysPreset(t *testing.T) {
+	restoreConfig(t)
+	SetConfig(Config{Keys: Keys{Name: "component"}})
+	SetConfigOverrides("preset,keys=slog,key.time=ts")
+
+	assert.Equal(t, Keys{Name: "component"}, GetConfig("").Keys)
+	keys := SlogKeys()
+	keys.Time = "ts"
+	assert.Equal(t, keys, GetConfig("preset").Keys)
+}
+
+func TestConfigString(t *testing.T) {
+	config := Config{
+		Level:        LevelDebug,
+		Format:       FormatTemplate,
+		EnableSource: true,
+		Template:     "{{.Level}}; {{.Msg}}",
+		Keys:         Keys{Time: "ts"},
+		CEF:          CEFConfig{Vendor: "Acme"},
+	}
+	assert.Equal(t, `level=debug,format=template,source=true,template="{{.Level}}; {{.Msg}}",cef.vendor=Acme,key.time=ts`, config.String())
+	assert.Equal(t, "", Config{}.String())
+}
+
+func TestConfigTextRoundTrip(t *testing.T) {
+	config := Config{
+		Level:        LevelWarn,
+		Output:       OutputStdout,
+		DisableColor: true,
+		Template:     ` "quoted", {{.Msg}} `,
+		TimeFormat:   "15:04:05",
+		Keys:         SlogKeys(),
+	}
+	text, err := config.MarshalText()
+	require.NoError(t, err)
+
+	var other Config
+	require.NoError(t, other.UnmarshalText(text))
+	assert.Equal(t, config, other)
+}
+
+func TestConfigUnmarshalTextWithInvalidValues(t *testing.T) {
+	config := Config{Level: LevelInfo}
+	err := config.UnmarshalText([]byte(`level=verbose,template="{{.Msg}}`))
+	assert.ErrorContains(t, err, `column 1: key "level": invalid level "verbose"`)
+	assert.ErrorContains(t, err, `column 15: key "template": invalid quoted value "{{.Msg}}`)
+	assert.Equal(t, Config{Level: LevelInfo}, config)
+}
+
+func TestParseConfigOverridesWithQuotedValues(t *testing.T) {
+	overrides, err := ParseConfigOverrides(`"re:^db\\.[a-z]{1,3}$",template="{{.Level}}, {{.Msg}}";net, template = "a;b" ,level=info`)
+	require.NoError(t, err)
+	require.Len(t, overrides, 2)
+
+	assert.Equal(t, `re:^db\.[a-z]{1,3}$`, overrides[0].Name)
+	assert.Equal(t, "{{.Level}}, {{.Msg}}", overrides[0].Config.Template)
+	assert.Equal(t, "net", overrides[1].Name)
+	assert.Equal(t, "a;b", overrides[1].Config.Template)
+	assert.Equal(t, LevelInfo, overrides[1].Config.Level)
+}
+
+func TestParseConfigOverridesWithInnerQuotes(t *testing.T) {
+	overrides, err := ParseConfigOverrides(`net,template={{.Msg}} "a;b";core,level=info`)
+	require.NoError(t, err)
+	require.Len(t, overrides, 3)
+	assert.Equal(t, `{{.Msg}} "a`, overrides[0].Config.Template)
+	assert.Equal(t, "core", overrides[2].Name)
+}
+
+func TestConfigOverrideString(t *testing.T) {
+	override := ConfigOverride{
+		Name:   "re:^a{1,3}$",
+		Config: Config{Level: LevelDebug},
+		Fields: []string{"level", "source"},
+	}
+	assert.Equal(t, `"re:^a{1,3}$",level=debug,source=false`, override.String())
+	assert.Equal(t, "net", ConfigOverride{Name: "net"}.String())
+
+	overrides, err := ParseConfigOverrides(override.String())
+	require.NoError(t, err)
+	assert.Equal(t, []ConfigOverride{override}, overrides)
+}
+
+func TestRemoveConfigOverride(t *testing.T) {
+	resetConfig(t, Config{Level: LevelError})
+	SetConfigOverrides("remove,level=debug;remove.*,format=json")
+
+	RemoveConfigOverride("remove")
+	assert.Equal(t, Config{Level: LevelError}, GetConfig("remove"))
+	assert.Equal(t, Config{Level: LevelError, Format: FormatJSON}, GetConfig("remove.child"))
+
+	RemoveConfigOverride("remove.*")
+	RemoveConfigOverride("missing")
+	assert.Equal(t, Config{Level: LevelError}, GetConfig("remove.child"))
+}
+
+func TestClearConfigOverrides(t *testing.T) {
+	resetConfig(t, Config{Level: LevelError})
+	SetConfigOverrides("clear,level=debug;clear.*,format=json")
+
+	ClearConfigOverrides()
+	assert.Equal(t, Config{Level: LevelError}, GetConfig("clear"))
+	assert.Equal(t, Config{Level: LevelError}, GetConfig("clear.child"))
+
+	SetConfigOverride("clear.*", Config{Level: LevelInfo})
+	assert.Equal(t, Config{Level: LevelInfo}, GetConfig("clear.child"))
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	resetConfig(t, Config{Level: LevelError})
+	SetConfigOverrides("snapshot,level=debug;snapshot.*,format=json")
+	snapshot := Snapshot()
+
+	SetConfig(Config{Level: LevelInfo})
+	RemoveConfigOverride("snapshot.*")
+	SetConfigOverride("snapshot", Config{Level: LevelWarn})
+	SetConfigOverride("other.*", Config{Level: LevelWarn})
+
+	for i := 0; i < 2; i++ {
+		Restore(snapshot)
+		assert.Equal(t, Config{Level: LevelError}, GetConfig(""))
+		assert.Equal(t, Config{Level: LevelDebug}, GetConfig("snapshot"))
+		assert.Equal(t, Config{Level: LevelDebug, Format: FormatJSON}, GetConfig("snapshot.child"))
+		assert.Equal(t, Config{Level: LevelError}, GetConfig("other.child"))
+
+		// changes after restoring do not change the snapshot
+		RemoveConfigOverride("snapshot.*")
+	}
+}
+
+func TestSetConfigOverridesWithAttrs(t *testing.T) {
+	resetConfig(t, Config{Attrs: map[string]string{"service": "defradb", "env": "prod"}})
+	SetConfigOverrides("attrs,attr.node_ID=1,attr.env=dev;attrs.child,attr.zone=eu")
+
+	assert.Equal(t, map[string]string{"service": "defradb", "env": "prod"}, GetConfig("").Attrs)
+	assert.Equal(t, map[string]string{"service": "defradb", "env": "dev", "node_ID": "1"}, GetConfig("attrs").Attrs)
+	assert.Equal(t, map[string]string{"service": "defradb", "env": "dev", "node_ID": "1", "zone": "eu"}, GetConfig("attrs.child").Attrs)
+	assert.Equal(t, []string{"attr.node_ID", "attr.env"}, defaultRegistry.state.overrides["attrs"].Fields)
+}
+
+func TestSetConfigOverrideWithAttrs(t *testing.T) {
+	resetConfig(t, Config{Level: LevelInfo, Attrs: map[string]string{"service": "defradb"}})
+	attrs := map[string]string{"node_id": "1"}
+	SetConfigOverride("attrs.override", Config{Attrs: attrs})
+
+	assert.Equal(t, Config{Attrs: map[string]string{"service": "defradb", "node_id": "1"}}, GetConfig("attrs.override"))
+	assert.Equal(t, map[string]string{"node_id": "1"}, attrs)
+}
+
+func TestConfigAttrsAreCopied(t *testing.T) {
+	registry := NewRegistry(Config{})
+	attrs := map[string]string{"service": "defradb"}
+	registry.SetConfig(Config{Attrs: attrs})
+	registry.SetConfigOverride("attrs", Config{Attrs: attrs})
+
+	attrs["service"] = "changed"
+	registry.GetConfig("").Attrs["service"] = "changed"
+	registry.GetConfig("attrs").Attrs["service"] = "changed"
+
+	assert.Equal(t, map[string]string{"service": "defradb"}, registry.GetConfig("").Attrs)
+	assert.Equal(t, map[string]string{"service": "defradb"}, registry.GetConfig("attrs").Attrs)
+}
+
+func TestDefaultConfigWithEnvAttrs(t *testing.T) {
+	t.Setenv("LOG_ATTRS", `service=defradb, tags="a,b"`)
+	assert.Equal(t, map[string]string{"service": "defradb", "tags": "a,b"}, DefaultConfig().Attrs)
+
+	t.Setenv("LOG_ATTRS", "service=defradb,zone")
+	assert.Equal(t, map[string]string{"service": "defradb"}, DefaultConfig().Attrs)
+	assert.ErrorContains(t, CheckConfig(), `LOG_ATTRS: invalid key value pair: "zone"`)
+}
+
+func TestConfigStringWithAttrs(t *testing.T) {
+	config := Config{Level: LevelInfo, Attrs: map[string]string{"service": "defradb", "tags": "a,b"}}
+	assert.Equal(t, `level=info,attr.service=defradb,attr.tags="a,b"`, config.String())
+
+	var other Config
+	require.NoError(t, other.UnmarshalText([]byte(config.String())))
+	assert.Equal(t, config, other)
 }
@@ -0,0 +1,80 @@
+package corelog
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// configDump contains the global config and the fields that are set
+// by each config override, using the same keys as config overrides.
+type configDump struct {
+	// Config contains all fields of the global config.
+	Config map[string]any `json:"config"`
+	// Overrides contains the fields that are set by each override.
+	Overrides map[string]map[string]any `json:"overrides"`
+}
+
+// DumpConfig returns the global config and all config overrides
+// of the default registry in the given format.
+func DumpConfig(format string) (string, error) {
+	return defaultRegistry.DumpConfig(format)
+}
+
+// DumpConfig returns the global config and all config overrides in the given
+// format, which is FormatText or FormatJSON in any case, e.g. for support bundles.
+//
+// The text format has two lines using the syntax of config overrides. The
+// first line contains the global config in the format of Config.String, and
+// the second line contains the overrides in the order they are applied, so
+// that SetConfigOverrides restores them. The JSON format is an object with
+// the global config and an object with the fields of each override.
+func (r *Registry) DumpConfig(format string) (string, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	switch strings.ToLower(format) {
+	case FormatText:
+		return r.state.dumpText(), nil
+	case FormatJSON:
+		data, err := json.Marshal(r.state.dump())
+		return string(data), err
+	}
+	return "", fmt.Errorf("unsupported dump format: %s", format)
+}
+
+// dump returns the config and config overrides.
+func (s configState) dump() configDump {
+	overrides := make(map[string]map[string]any, len(s.overrides))
+	for name, override := range s.overrides {
+		overrides[name] = override.values()
+	}
+	return configDump{Config: configValues(s.config), Overrides: overrides}
+}
+
+// configValues returns the values of all config fields
+// keyed by the same keys as config overrides.
+func configValues(config Config) map[string]any {
+	values := make(map[string]any)
+	for _, field := range allConfigFields(&config) {
+		if field.get != nil {
+			values[field.key] = field.get(&config)
+		}
+	}
+	return values
+}
+
+// dumpText returns the config and config overrides as text.
+func (s configState) dumpText() string {
+	var overrides []string
+	// patterns are applied before names in the order they were set
+	for _, pattern := range s.patterns {
+		overrides = append(overrides, s.overrides[pattern.name].String())
+	}
+	for _, name := range sortedKeys(s.overrides) {
+		if _, ok := newOverridePattern(name); !ok {
+			overrides = append(overrides, s.overrides[name].String())
+		}
+	}
+	return s.config.String() + "\n" + strings.Join(overrides, ";") + "\n"
+}
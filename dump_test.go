@@ -0,0 +1,75 @@
+package corelog
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpConfigText(t *testing.T) {
+	resetConfig(t, Config{Level: LevelInfo, Format: FormatJSON})
+	SetConfigOverrides(`net,level=debug;*.store,source=false;core,template="{{.Msg}}; done";re:^db,level=error`)
+
+	text, err := DumpConfig(FormatText)
+	require.NoError(t, err)
+	assert.Equal(t, "level=info,format=json\n"+
+		`*.store,source=false;re:^db,level=error;core,template="{{.Msg}}; done";net,level=debug`+"\n", text)
+}
+
+func TestDumpConfigTextRoundTrip(t *testing.T) {
+	resetConfig(t, Config{Level: LevelWarn, Template: "{{.Level}}, {{.Msg}}"})
+	SetConfigOverrides(`dump.b,output=stdout;dump.*,level=debug;dump.a,keys=slog`)
+	before := Snapshot().state
+
+	text, err := DumpConfig(FormatText)
+	require.NoError(t, err)
+	global, overrides, _ := strings.Cut(strings.TrimSuffix(text, "\n"), "\n")
+
+	var config Config
+	require.NoError(t, config.UnmarshalText([]byte(global)))
+	resetConfig(t, config)
+	SetConfigOverrides(overrides)
+
+	for _, name := range []string{"", "dump.a", "dump.b", "dump.c"} {
+		assert.Equal(t, before.get(name), GetConfig(name), name)
+	}
+}
+
+func TestDumpConfigJSON(t *testing.T) {
+	resetConfig(t, Config{Level: LevelInfo})
+	SetConfigOverrides("net,format=json")
+
+	text, err := DumpConfig(FormatJSON)
+	require.NoError(t, err)
+
+	var dump configDump
+	require.NoError(t, json.Unmarshal([]byte(text), &dump))
+	assert.Equal(t, configValues(Config{Level: LevelInfo}), dump.Config)
+	assert.Equal(t, LevelInfo, dump.Config["level"])
+	assert.Equal(t, false, dump.Config["source"])
+	assert.Equal(t, map[string]map[string]any{"net": {"format": FormatJSON}}, dump.Overrides)
+}
+
+func TestDumpConfigWithUpperCaseFormat(t *testing.T) {
+	resetConfig(t, Config{Level: LevelInfo})
+
+	text, err := DumpConfig("JSON")
+	require.NoError(t, err)
+	expected, err := DumpConfig(FormatJSON)
+	require.NoError(t, err)
+	assert.Equal(t, expected, text)
+
+	text, err = DumpConfig("Text")
+	require.NoError(t, err)
+	expected, err = DumpConfig(FormatText)
+	require.NoError(t, err)
+	assert.Equal(t, expected, text)
+}
+
+func TestDumpConfigWithUnsupportedFormat(t *testing.T) {
+	_, err := DumpConfig(FormatCEF)
+	assert.ErrorContains(t, err, "unsupported dump format: cef")
+}
@@ -0,0 +1,114 @@
+package corelog
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultEnvPrefix is the default prefix of environment variable names.
+const DefaultEnvPrefix = "LOG"
+
+const (
+	// envConfigFile is the environment variable for the config file path.
+	envConfigFile = "CONFIG_FILE"
+	// envConfigWatch is the environment variable for the config file watch interval.
+	envConfigWatch = "CONFIG_WATCH"
+	// envOverrides is the environment variable for config overrides.
+	envOverrides = "OVERRIDES"
+	// envAttrs is the environment variable for static attributes.
+	envAttrs = "ATTRS"
+)
+
+var (
+	envMutex    sync.RWMutex
+	envPrefix   = DefaultEnvPrefix
+	envFallback bool
+	// envWatcher stops watching the config file
+	// set by the environment, or is nil
+	envWatcher func()
+)
+
+// SetEnvPrefix sets the prefix of all environment variable names and reloads
+// the config from the environment.
+//
+// Names are the prefix followed by an underscore and the name without the
+// LOG_ prefix, e.g. SetEnvPrefix("DEFRA_LOG", false) reads DEFRA_LOG_LEVEL,
+// DEFRA_LOG_OVERRIDES, and DEFRA_LOG_CONFIG_FILE instead of LOG_LEVEL,
+// LOG_OVERRIDES, and LOG_CONFIG_FILE. If fallback is true the unprefixed
+// LOG_ names are used for variables that are not set with the prefix.
+// An empty prefix restores the default prefix.
+//
+// The package reads the LOG_ names when it is initialized, so SetEnvPrefix
+// should be called at startup before the config is changed in other ways,
+// which are replaced by the config from the environment.
+func SetEnvPrefix(prefix string, fallback bool) {
+	prefix = strings.TrimSuffix(prefix, "_")
+	if prefix == "" {
+		prefix = DefaultEnvPrefix
+	}
+	envMutex.Lock()
+	envPrefix, envFallback = prefix, fallback
+	envMutex.Unlock()
+
+	loadEnvConfig()
+}
+
+// getEnv returns the name and value of the environment variable with the
+// given name and the current prefix, or of the unprefixed variable if it
+// is not set and fallback is enabled.
+func getEnv(name string) (string, string) {
+	envMutex.RLock()
+	prefix, fallback := envPrefix, envFallback
+	envMutex.RUnlock()
+
+	key := prefix + "_" + name
+	if value := os.Getenv(key); value != "" || !fallback || prefix == DefaultEnvPrefix {
+		return key, value
+	}
+	if value := os.Getenv(DefaultEnvPrefix + "_" + name); value != "" {
+		return DefaultEnvPrefix + "_" + name, value
+	}
+	return key, ""
+}
+
+// replaceEnvConfig replaces the config and config overrides of the default
+// registry with the ones of a config file followed by LOG_OVERRIDES, so that
+// reloaded config files do not replace the overrides of the environment.
+func replaceEnvConfig(config Config, overrides map[string]ConfigOverride) {
+	_, text := getEnv(envOverrides)
+	ordered, _ := ParseConfigOverrides(text)
+	defaultRegistry.replaceConfig(config, overrides, ordered...)
+}
+
+// loadEnvConfig replaces the config and all config overrides with the config
+// from the environment variables, followed by the config file and the config
+// overrides, and watches the config file if a watch interval is set.
+func loadEnvConfig() {
+	config := DefaultConfig()
+	var overrides map[string]ConfigOverride
+
+	_, path := getEnv(envConfigFile)
+	_, watch := getEnv(envConfigWatch)
+	if path != "" {
+		// invalid files are reported by CheckConfig
+		if fileConfig, fileOverrides, err := readConfigFile(path); err == nil {
+			config, overrides = fileConfig, fileOverrides
+		}
+	}
+	replaceEnvConfig(config, overrides)
+
+	envMutex.Lock()
+	if envWatcher != nil {
+		envWatcher()
+		envWatcher = nil
+	}
+	if interval, err := time.ParseDuration(watch); err == nil && path != "" {
+		// only the watcher of the environment
+		// applies LOG_OVERRIDES after each reload
+		watcher := newConfigWatcher(path, internalLogger, replaceEnvConfig)
+		envWatcher = watchConfigFile(watcher, interval)
+	}
+	envMutex.Unlock()
+}
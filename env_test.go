@@ -0,0 +1,95 @@
+package corelog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// restoreEnvPrefix restores the default env prefix when the test ends.
+func restoreEnvPrefix(t *testing.T) {
+	restoreConfig(t)
+	t.Cleanup(func() {
+		SetEnvPrefix("", false)
+	})
+}
+
+func TestSetEnvPrefix(t *testing.T) {
+	restoreEnvPrefix(t)
+	t.Setenv("LOG_LEVEL", "error")
+	t.Setenv("TEST_LOG_LEVEL", "debug")
+	t.Setenv("TEST_LOG_FORMAT", "json")
+	t.Setenv("TEST_LOG_OVERRIDES", "env.prefix,level=warn")
+
+	SetEnvPrefix("TEST_LOG_", false)
+	assert.Equal(t, LevelDebug, GetConfig("").Level)
+	assert.Equal(t, FormatJSON, GetConfig("").Format)
+	assert.Equal(t, LevelWarn, GetConfig("env.prefix").Level)
+}
+
+func TestSetEnvPrefixReplacesOverrides(t *testing.T) {
+	restoreEnvPrefix(t)
+	t.Setenv("LOG_OVERRIDES", "env.unprefixed,level=error")
+	t.Setenv("TEST_LOG_OVERRIDES", "env.prefixed,level=warn")
+	SetEnvPrefix("", false)
+	require.Equal(t, LevelError, GetConfig("env.unprefixed").Level)
+
+	SetEnvPrefix("TEST_LOG", false)
+	assert.Equal(t, "", GetConfig("env.unprefixed").Level)
+	assert.Equal(t, LevelWarn, GetConfig("env.prefixed").Level)
+}
+
+func TestSetEnvPrefixWithoutFallback(t *testing.T) {
+	restoreEnvPrefix(t)
+	t.Setenv("LOG_LEVEL", "error")
+	t.Setenv("TEST_LOG_LEVEL", "")
+
+	SetEnvPrefix("TEST_LOG", false)
+	assert.Equal(t, "", GetConfig("").Level)
+}
+
+func TestSetEnvPrefixWithFallback(t *testing.T) {
+	restoreEnvPrefix(t)
+	t.Setenv("LOG_LEVEL", "error")
+	t.Setenv("LOG_FORMAT", "json")
+	t.Setenv("TEST_LOG_LEVEL", "debug")
+	t.Setenv("TEST_LOG_FORMAT", "")
+
+	SetEnvPrefix("TEST_LOG", true)
+	assert.Equal(t, LevelDebug, GetConfig("").Level)
+	assert.Equal(t, FormatJSON, GetConfig("").Format)
+}
+
+func TestSetEnvPrefixCheckConfig(t *testing.T) {
+	restoreEnvPrefix(t)
+	t.Setenv("LOG_LEVEL", "")
+	t.Setenv("TEST_LOG_LEVEL", "verbose")
+	t.Setenv("TEST_LOG_OVERRIDES", "env.check,levle=error")
+
+	SetEnvPrefix("TEST_LOG", false)
+	err := CheckConfig()
+	require.Error(t, err)
+	assert.ErrorContains(t, err, `TEST_LOG_LEVEL: invalid level "verbose"`)
+	assert.ErrorContains(t, err, `TEST_LOG_OVERRIDES: column 11: override "env.check": key "levle": unknown config key`)
+}
+
+func TestGetEnv(t *testing.T) {
+	restoreEnvPrefix(t)
+	t.Setenv("LOG_THEME", "light")
+	t.Setenv("TEST_LOG_THEME", "")
+
+	name, value := getEnv("THEME")
+	assert.Equal(t, "LOG_THEME", name)
+	assert.Equal(t, "light", value)
+
+	SetEnvPrefix("TEST_LOG", true)
+	name, value = getEnv("THEME")
+	assert.Equal(t, "LOG_THEME", name)
+	assert.Equal(t, "light", value)
+
+	SetEnvPrefix("TEST_LOG", false)
+	name, value = getEnv("THEME")
+	assert.Equal(t, "TEST_LOG_THEME", name)
+	assert.Equal(t, "", value)
+}
@@ -0,0 +1,156 @@
+package corelog
+
+import (
+	"flag"
+	"strings"
+)
+
+const (
+	// flagPrefix is the prefix of the names of config flags.
+	flagPrefix = "log-"
+	// flagOverrides is the name of the config overrides flag without the prefix.
+	flagOverrides = "overrides"
+	// flagAttrs is the name of the static attributes flag without the prefix.
+	flagAttrs = "attrs"
+)
+
+// BindFlags registers a flag for every config field, for static attributes,
+// and for config overrides on the flag set, e.g. -log-level, -log-format,
+// -log-cef-vendor, -log-attrs, and -log-overrides.
+//
+// The defaults are the values of the environment variables, and flags are
+// applied to the default registry when they are parsed, in the order they
+// appear on the command line. Attributes use the same syntax as LOG_ATTRS
+// and overrides use the same syntax as SetConfigOverrides, and both are
+// added to the existing attributes and overrides.
+//
+// The flags can be registered on a pflag flag set with the corelog/pflag package.
+func BindFlags(fs *flag.FlagSet) {
+	for _, field := range configFields {
+		fs.Var(newConfigFlag(field), flagName(field.key), field.usage)
+	}
+	fs.Var(newAttrsFlag(), flagPrefix+flagAttrs, "adds static attributes (name=value,name=value)")
+	fs.Var(newOverridesFlag(), flagPrefix+flagOverrides, "sets logger specific overrides")
+}
+
+// flagName returns the name of the flag for the config key.
+func flagName(key string) string {
+	return flagPrefix + strings.ReplaceAll(key, ".", "-")
+}
+
+// configFlag is a flag that sets a field of the global config.
+type configFlag struct {
+	field configField
+	value string
+}
+
+func newConfigFlag(field configField) *configFlag {
+	_, value := getEnv(field.env)
+	return &configFlag{field: field, value: value}
+}
+
+func (f *configFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.value
+}
+
+func (f *configFlag) Set(value string) error {
+	err := defaultRegistry.change(func() error {
+		return setConfigField(&defaultRegistry.state.config, f.field, value)
+	})
+	if err != nil {
+		return err
+	}
+	f.value = value
+	return nil
+}
+
+func (f *configFlag) Type() string {
+	if f.IsBoolFlag() {
+		return "bool"
+	}
+	return "string"
+}
+
+// IsBoolFlag returns true if the flag can be set without a value.
+func (f *configFlag) IsBoolFlag() bool {
+	if f == nil || f.field.get == nil {
+		return false
+	}
+	_, ok := f.field.get(&Config{}).(bool)
+	return ok
+}
+
+// attrsFlag is a flag that adds static attributes to the global config.
+type attrsFlag struct {
+	value string
+}
+
+func newAttrsFlag() *attrsFlag {
+	_, value := getEnv(envAttrs)
+	return &attrsFlag{value: value}
+}
+
+func (f *attrsFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.value
+}
+
+// Set adds the attributes if they are all valid.
+func (f *attrsFlag) Set(value string) error {
+	if _, err := parseAttrs(value, nil); err != nil {
+		return err
+	}
+	defaultRegistry.change(func() error {
+		config := &defaultRegistry.state.config
+		config.Attrs, _ = parseAttrs(value, config.Attrs)
+		return nil
+	})
+	f.value = value
+	return nil
+}
+
+func (f *attrsFlag) Type() string {
+	return "string"
+}
+
+// overridesFlag is a flag that adds config overrides.
+type overridesFlag struct {
+	value string
+}
+
+func newOverridesFlag() *overridesFlag {
+	_, value := getEnv(envOverrides)
+	return &overridesFlag{value: value}
+}
+
+func (f *overridesFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.value
+}
+
+// Set adds the overrides if they are all valid.
+func (f *overridesFlag) Set(value string) error {
+	overrides, err := ParseConfigOverrides(value)
+	if err != nil {
+		return err
+	}
+	defaultRegistry.change(func() error {
+		for _, override := range overrides {
+			defaultRegistry.state.setOverride(override)
+		}
+		return nil
+	})
+	f.value = value
+	return nil
+}
+
+func (f *overridesFlag) Type() string {
+	return "string"
+}
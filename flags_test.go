@@ -0,0 +1,73 @@
+package corelog
+
+import (
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindFlags(t *testing.T) {
+	restoreConfig(t)
+	SetConfig(Config{Level: LevelInfo})
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	BindFlags(fs)
+
+	err := fs.Parse([]string{"-log-level=debug", "-log-source", "-log-cef-vendor", "Acme", "-log-overrides", "flags.std,level=error"})
+	require.NoError(t, err)
+
+	assert.Equal(t, LevelDebug, GetConfig("").Level)
+	assert.Equal(t, true, GetConfig("").EnableSource)
+	assert.Equal(t, "Acme", GetConfig("").CEF.Vendor)
+	assert.Equal(t, LevelError, GetConfig("flags.std").Level)
+	assert.Equal(t, "debug", fs.Lookup("log-level").Value.String())
+}
+
+func TestBindFlagsWithInvalidValue(t *testing.T) {
+	restoreConfig(t)
+	SetConfig(Config{Level: LevelInfo})
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	BindFlags(fs)
+
+	err := fs.Parse([]string{"-log-level=verbose"})
+	assert.ErrorContains(t, err, `invalid level "verbose"`)
+	assert.Equal(t, LevelInfo, GetConfig("").Level)
+
+	err = fs.Parse([]string{"-log-overrides=flags.invalid,levle=error"})
+	assert.ErrorContains(t, err, `unknown config key`)
+}
+
+func TestBindFlagsWithEnvDefaults(t *testing.T) {
+	t.Setenv("LOG_FORMAT", "json")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	BindFlags(fs)
+	assert.Equal(t, "json", fs.Lookup("log-format").DefValue)
+	assert.Equal(t, "", fs.Lookup("log-level").DefValue)
+}
+
+func TestBindFlagsCoversAllFields(t *testing.T) {
+	for _, field := range configFields {
+		assert.NotEmpty(t, field.usage, field.key)
+	}
+}
+
+func TestBindFlagsWithAttrs(t *testing.T) {
+	resetConfig(t, Config{Attrs: map[string]string{"service": "node"}})
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	BindFlags(fs)
+
+	err := fs.Parse([]string{"-log-attrs=region=eu", "-log-attrs", `tags="a,b"`})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"service": "node", "region": "eu", "tags": "a,b"}, GetConfig("").Attrs)
+
+	err = fs.Parse([]string{"-log-attrs=zone"})
+	assert.ErrorContains(t, err, `invalid key value pair: "zone"`)
+}
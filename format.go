@@ -0,0 +1,77 @@
+package corelog
+
+import (
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// FormatFactory returns a handler for a named logger that writes to the given output.
+//
+// The handler is created with the latest config values for the named logger.
+// Records are only passed to the handler if their level is enabled for the
+// logger, and the logger name is included as a record attribute.
+type FormatFactory func(config Config, name string, output io.Writer) slog.Handler
+
+var (
+	formatsMutex sync.RWMutex
+	formats      = map[string]FormatFactory{
+		FormatText: func(config Config, name string, output io.Writer) slog.Handler {
+			return newTintHandler(config, name, output)
+		},
+		FormatJSON: func(config Config, name string, output io.Writer) slog.Handler {
+			return newJSONHandler(config, name, output)
+		},
+		FormatPretty: func(config Config, name string, output io.Writer) slog.Handler {
+			return newPrettyHandler(config, name, output)
+		},
+		FormatTemplate: func(config Config, name string, output io.Writer) slog.Handler {
+			return newTemplateHandler(config, name, output)
+		},
+		FormatCEF: func(config Config, name string, output io.Writer) slog.Handler {
+			return newCEFHandler(config, name, output)
+		},
+		FormatCBOR: func(config Config, name string, output io.Writer) slog.Handler {
+			return newBinaryHandler(config, name, output)
+		},
+		FormatMsgpack: func(config Config, name string, output io.Writer) slog.Handler {
+			return newBinaryHandler(config, name, output)
+		},
+	}
+)
+
+// RegisterFormat registers a format that can be selected by name
+// from the config Format value.
+//
+// Registering a format with an existing name replaces the existing format.
+func RegisterFormat(name string, factory FormatFactory) {
+	formatsMutex.Lock()
+	defer formatsMutex.Unlock()
+	formats[strings.ToLower(name)] = factory
+	registrations.Add(1)
+}
+
+// getFormat returns the registered lower case name and the
+// factory of the format with the given name.
+func getFormat(name string) (string, FormatFactory) {
+	formatsMutex.RLock()
+	defer formatsMutex.RUnlock()
+
+	name = strings.ToLower(name)
+	if factory, ok := formats[name]; ok {
+		return name, factory
+	}
+	// default to text if no value is set
+	// or the set value is invalid
+	return FormatText, formats[FormatText]
+}
+
+// hasFormat returns true if a format with the given name is registered.
+func hasFormat(name string) bool {
+	formatsMutex.RLock()
+	defer formatsMutex.RUnlock()
+
+	_, ok := formats[strings.ToLower(name)]
+	return ok
+}
@@ -0,0 +1,55 @@
+package corelog
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterFormat(t *testing.T) {
+	resetConfig(t, Config{})
+	var buf bytes.Buffer
+	RegisterFormat("Custom", func(config Config, name string, output io.Writer) slog.Handler {
+		return slog.NewTextHandler(&buf, nil)
+	})
+	SetConfigOverride("format", Config{Format: "custom"})
+
+	logger := NewLogger("format")
+	logger.Info("message", String("key", "value"))
+
+	require.NotEmpty(t, buf.String())
+	assert.Contains(t, buf.String(), "msg=message $name=format key=value")
+}
+
+func TestRegisterFormatAfterLogging(t *testing.T) {
+	var fallback, custom bytes.Buffer
+	registry := NewRegistry(Config{Format: "registered-later", TimeFormat: TimeFormatNone, DisableColor: true})
+	registry.SetOutput(OutputStderr, &fallback)
+	logger := registry.NewLogger("format")
+
+	logger.Info("first")
+	RegisterFormat("registered-later", func(config Config, name string, output io.Writer) slog.Handler {
+		return slog.NewTextHandler(&custom, nil)
+	})
+	logger.Info("second")
+
+	assert.Equal(t, "INF format first\n", fallback.String())
+	assert.Contains(t, custom.String(), "msg=second $name=format")
+}
+
+func TestGetFormatWithInvalidName(t *testing.T) {
+	var buf bytes.Buffer
+	format, factory := getFormat("invalid")
+	assert.Equal(t, FormatText, format)
+	handler := factory(Config{}, "test", &buf)
+
+	record := slog.NewRecord(time.Time{}, slog.LevelInfo, "message", 0)
+	require.NoError(t, handler.Handle(context.Background(), record))
+	assert.Equal(t, "INF test message\n", buf.String())
+}
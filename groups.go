@@ -0,0 +1,102 @@
+package corelog
+
+import "log/slog"
+
+// attrGroups contains the attributes and groups added to a handler
+// via calls to WithAttrs and WithGroup.
+type attrGroups struct {
+	// groups contains the names of the open groups
+	groups []string
+	// attrs contains the attributes for each group level,
+	// where attrs[0] are the top level attributes and
+	// attrs[i] are the attributes of groups[i-1]
+	attrs [][]slog.Attr
+}
+
+// withAttrs returns a copy of g with the given attributes added to the innermost group.
+func (g attrGroups) withAttrs(attrs []slog.Attr) attrGroups {
+	if len(attrs) == 0 {
+		return g
+	}
+	other := g.clone()
+	last := len(other.groups)
+	other.attrs[last] = append(other.attrs[last][:len(other.attrs[last]):len(other.attrs[last])], attrs...)
+	return other
+}
+
+// withGroup returns a copy of g with the given group opened.
+func (g attrGroups) withGroup(name string) attrGroups {
+	if name == "" {
+		return g
+	}
+	other := g.clone()
+	other.groups = append(other.groups, name)
+	other.attrs = append(other.attrs, nil)
+	return other
+}
+
+// clone returns a copy of g that can be safely modified.
+func (g attrGroups) clone() attrGroups {
+	attrs := make([][]slog.Attr, len(g.groups)+1)
+	copy(attrs, g.attrs)
+	return attrGroups{
+		groups: g.groups[:len(g.groups):len(g.groups)],
+		attrs:  attrs,
+	}
+}
+
+// resolve returns the complete list of attributes for the given record,
+// with the record attributes added to the innermost group.
+//
+// Values are resolved, empty attributes are dropped, and groups without
+// a key are inlined into their parent.
+func (g attrGroups) resolve(record slog.Record) []slog.Attr {
+	var inner []slog.Attr
+	if len(g.attrs) > len(g.groups) {
+		inner = append(inner, g.attrs[len(g.groups)]...)
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		inner = append(inner, attr)
+		return true
+	})
+	inner = resolveAttrs(inner)
+	for i := len(g.groups) - 1; i >= 0; i-- {
+		var outer []slog.Attr
+		if len(g.attrs) > i {
+			outer = resolveAttrs(g.attrs[i])
+		}
+		if len(inner) > 0 {
+			outer = append(outer, slog.Attr{Key: g.groups[i], Value: slog.GroupValue(inner...)})
+		}
+		inner = outer
+	}
+	return inner
+}
+
+// resolveAttrs resolves all values in the given attributes.
+//
+// Empty attributes and groups are dropped, and groups without
+// a key are inlined into their parent.
+func resolveAttrs(attrs []slog.Attr) []slog.Attr {
+	resolved := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		attr.Value = attr.Value.Resolve()
+		if attr.Equal(slog.Attr{}) {
+			continue // empty attribute
+		}
+		if attr.Value.Kind() != slog.KindGroup {
+			resolved = append(resolved, attr)
+			continue
+		}
+		group := resolveAttrs(attr.Value.Group())
+		if len(group) == 0 {
+			continue // empty group
+		}
+		if attr.Key == "" {
+			resolved = append(resolved, group...)
+			continue // inline group
+		}
+		resolved = append(resolved, slog.Attr{Key: attr.Key, Value: slog.GroupValue(group...)})
+	}
+	return resolved
+}
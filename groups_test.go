@@ -0,0 +1,52 @@
+package corelog
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttrGroupsResolve(t *testing.T) {
+	groups := attrGroups{}.
+		withAttrs([]slog.Attr{slog.String("a", "1")}).
+		withGroup("first").
+		withAttrs([]slog.Attr{slog.String("b", "2")}).
+		withGroup("second")
+
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, "message", 0)
+	record.AddAttrs(slog.String("c", "3"))
+
+	expected := []slog.Attr{
+		slog.String("a", "1"),
+		slog.Group("first",
+			slog.String("b", "2"),
+			slog.Group("second", slog.String("c", "3")),
+		),
+	}
+	assert.Equal(t, expected, groups.resolve(record))
+}
+
+func TestAttrGroupsResolveDropsEmptyGroups(t *testing.T) {
+	groups := attrGroups{}.withGroup("empty")
+
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, "message", 0)
+	record.AddAttrs(slog.Group("", slog.String("inline", "value")), slog.Group("none"))
+
+	expected := []slog.Attr{
+		slog.Group("empty", slog.String("inline", "value")),
+	}
+	assert.Equal(t, expected, groups.resolve(record))
+	assert.Empty(t, attrGroups{}.withGroup("empty").resolve(slog.Record{}))
+}
+
+func TestAttrGroupsAreImmutable(t *testing.T) {
+	base := attrGroups{}.withAttrs([]slog.Attr{slog.String("a", "1")})
+	first := base.withAttrs([]slog.Attr{slog.String("b", "2")})
+	second := base.withAttrs([]slog.Attr{slog.String("c", "3")})
+
+	assert.Equal(t, []slog.Attr{slog.String("a", "1")}, base.resolve(slog.Record{}))
+	assert.Equal(t, []slog.Attr{slog.String("a", "1"), slog.String("b", "2")}, first.resolve(slog.Record{}))
+	assert.Equal(t, []slog.Attr{slog.String("a", "1"), slog.String("c", "3")}, second.resolve(slog.Record{}))
+}
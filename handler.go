@@ -4,122 +4,254 @@ package corelog
 
 import (
 	"context"
+	"encoding"
+	"io"
 	"log/slog"
-	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/lmittmann/tint"
-	"golang.org/x/term"
-)
-
-const (
-	// nameKey is the key for the logger name attribute
-	nameKey = "$name"
-	// stackKey is the key for the logger stack attribute
-	stackKey = "$stack"
-	// errorKey is the key for the logger error attribute
-	errorKey = "$err"
-	// msgKey is the key for the logger message attribute
-	msgKey = "$msg"
-	// timeKey is the key for the logger time attribute
-	timeKey = "$time"
-	// levelKey is the key for the logger level attribute
-	levelKey = "$level"
-	// sourceKey is the key for the logger source attribute
-	sourceKey = "$source"
 )
 
 type namedHandler struct {
-	name  string
-	attrs []slog.Attr
-	group string
+	name     string
+	registry *Registry
+	attrs    []slog.Attr
+	group    string
+	// cache contains the last built handler,
+	// or is nil if handlers are not cached
+	cache *atomic.Pointer[cachedHandler]
+}
+
+// cachedHandler is a handler built from the config of a named logger
+// at a version of the registry and of the formats and themes.
+type cachedHandler struct {
+	version       uint64
+	registrations uint64
+	config        Config
+	handler       slog.Handler
 }
 
 func (h namedHandler) Enabled(ctx context.Context, level slog.Level) bool {
-	return level >= namedLeveler(h.name).Level()
+	return level >= configLevel(h.handler().config)
 }
 
 func (h namedHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	return &namedHandler{
-		name:  h.name,
-		group: h.group,
-		attrs: attrs,
+		name:     h.name,
+		registry: h.registry,
+		group:    h.group,
+		attrs:    attrs,
+		cache:    new(atomic.Pointer[cachedHandler]),
 	}
 }
 
 func (h namedHandler) WithGroup(name string) slog.Handler {
 	return &namedHandler{
-		name:  h.name,
-		attrs: h.attrs,
-		group: name,
+		name:     h.name,
+		registry: h.registry,
+		attrs:    h.attrs,
+		group:    name,
+		cache:    new(atomic.Pointer[cachedHandler]),
 	}
 }
 
 func (h namedHandler) Handle(ctx context.Context, record slog.Record) error {
-	config := GetConfig(h.name)
-
-	var output *os.File
-	switch config.Output {
-	case OutputStdout:
-		output = os.Stdout
-	default:
-		// default to os.Stderr if no value is set
-		// or the set value is invalid
-		output = os.Stderr
-	}
+	return h.handler().handler.Handle(ctx, record)
+}
+
+// config returns the config of the cached handler.
+func (h namedHandler) config() Config {
+	return h.handler().config
+}
 
-	var handler slog.Handler
-	switch config.Format {
-	case FormatJSON:
-		handler = newJSONHandler(config, h.name, output)
-	default:
-		// default to tint.Handler if no value is set
-		// or the set value is invalid
-		handler = newTintHandler(config, h.name, output)
-		// prepend logger name to message
-		record.Message = h.name + " " + record.Message
+// handler returns the cached handler if the config and outputs of the
+// registry and the registered formats and themes have not changed since
+// it was built, or builds a new handler.
+func (h namedHandler) handler() *cachedHandler {
+	registered := registrations.Load()
+	if h.cache != nil {
+		cached := h.cache.Load()
+		if cached != nil && cached.version == h.registry.version.Load() && cached.registrations == registered {
+			return cached
+		}
 	}
+	config, version := h.registry.getConfig(h.name)
+	output := h.registry.output(config.Output)
 
+	handler := newHandler(config, h.name, output)
+	if len(config.Attrs) > 0 {
+		handler = handler.WithAttrs(configAttrs(config))
+	}
 	if len(h.attrs) > 0 {
 		handler = handler.WithAttrs(h.attrs)
 	}
 	if len(h.group) > 0 {
 		handler = handler.WithGroup(h.group)
 	}
-	return handler.Handle(ctx, record)
+	cached := &cachedHandler{version: version, registrations: registered, config: config, handler: handler}
+	if h.cache != nil {
+		h.cache.Store(cached)
+	}
+	return cached
+}
+
+// configAttrs returns the static attributes of the config sorted by name.
+func configAttrs(config Config) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(config.Attrs))
+	for _, name := range sortedKeys(config.Attrs) {
+		attrs = append(attrs, slog.String(name, config.Attrs[name]))
+	}
+	return attrs
 }
 
-func newTintHandler(config Config, name string, output *os.File) slog.Handler {
-	isTerminal := term.IsTerminal(int(output.Fd()))
-	return tint.NewHandler(output, &tint.Options{
+// newHandler returns a handler for the config format that writes to the given output.
+//
+// The config format is replaced with the registered name of the format,
+// so that factories do not need to compare format names case insensitively.
+func newHandler(config Config, name string, output io.Writer) slog.Handler {
+	format, factory := getFormat(config.Format)
+	config.Format = format
+	return factory(config, name, output)
+}
+
+func newTintHandler(config Config, name string, output io.Writer) slog.Handler {
+	keys := config.Keys.withDefaults()
+	depth := detectColor(config, output)
+	paint := newPainter(config, depth)
+	handler := tint.NewHandler(output, &tint.Options{
 		AddSource: config.EnableSource,
-		Level:     namedLeveler(name),
-		NoColor:   !isTerminal || config.DisableColor,
+		Level:     configLevel(config),
+		NoColor:   depth == colorNone,
 		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
-			// ignore name as it is prended to message
-			if attr.Key == nameKey {
-				return slog.Attr{}
+			// color built-in values with the theme
+			switch value := attr.Value.Any().(type) {
+			case time.Time:
+				if attr.Key != slog.TimeKey {
+					return attr
+				}
+				text, ok := timeText(config, value, time.StampMilli)
+				if !ok {
+					return slog.Attr{}
+				}
+				return slog.String(attr.Key, paint.paint(text, paint.theme.Time))
+			case slog.Level:
+				if attr.Key != slog.LevelKey {
+					return attr
+				}
+				return slog.String(attr.Key, paint.level(value))
+			case *slog.Source:
+				if attr.Key != slog.SourceKey {
+					return attr
+				}
+				dir, file := filepath.Split(value.File)
+				text := filepath.Join(filepath.Base(dir), file) + ":" + strconv.Itoa(value.Line)
+				return slog.String(attr.Key, paint.paint(text, paint.theme.Source))
 			}
 			return attr
 		},
 	})
+	// prepend logger name to message
+	return &tintHandler{handler: handler, prefix: paint.name(name) + " ", keys: keys, paint: paint}
 }
 
-func newJSONHandler(config Config, name string, output *os.File) *slog.JSONHandler {
+func newJSONHandler(config Config, name string, output io.Writer) *slog.JSONHandler {
+	keys := config.Keys.withDefaults()
 	return slog.NewJSONHandler(output, &slog.HandlerOptions{
 		AddSource: config.EnableSource,
-		Level:     namedLeveler(name),
+		Level:     configLevel(config),
 		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
 			switch attr.Key {
 			case slog.TimeKey:
-				attr.Key = timeKey
+				attr = replaceTime(config, attr)
+				if attr.Key != "" {
+					attr.Key = keys.Time
+				}
 			case slog.LevelKey:
-				attr.Key = levelKey
+				attr.Key = keys.Level
 			case slog.MessageKey:
-				attr.Key = msgKey
+				attr.Key = keys.Message
 			case slog.SourceKey:
-				attr.Key = sourceKey
+				attr.Key = keys.Source
 			}
 			return attr
 		},
 	})
 }
+
+// tintHandler is an slog.Handler that prepends the logger name to messages
+// and appends the attributes colored with the theme, as tint does not color
+// attribute keys and values.
+type tintHandler struct {
+	handler slog.Handler
+	prefix  string
+	keys    Keys
+	paint   painter
+	attrs   attrGroups
+}
+
+func (h *tintHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *tintHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	other := *h
+	other.attrs = h.attrs.withAttrs(attrs)
+	return &other
+}
+
+func (h *tintHandler) WithGroup(name string) slog.Handler {
+	other := *h
+	other.attrs = h.attrs.withGroup(name)
+	return &other
+}
+
+func (h *tintHandler) Handle(ctx context.Context, record slog.Record) error {
+	// name is part of the message so it
+	// is removed from the record attributes
+	attrs := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		if attr.Key != h.keys.Name {
+			attrs.AddAttrs(attr)
+		}
+		return true
+	})
+	var buf strings.Builder
+	buf.WriteString(h.prefix)
+	buf.WriteString(record.Message)
+	h.appendAttrs(&buf, "", h.attrs.resolve(attrs))
+	return h.handler.Handle(ctx, slog.NewRecord(record.Time, record.Level, buf.String(), record.PC))
+}
+
+// appendAttrs writes the attributes as colored key value pairs,
+// where group keys are joined with ".".
+func (h *tintHandler) appendAttrs(buf *strings.Builder, prefix string, attrs []slog.Attr) {
+	for _, attr := range attrs {
+		if attr.Value.Kind() == slog.KindGroup {
+			h.appendAttrs(buf, prefix+attr.Key+".", attr.Value.Group())
+			continue
+		}
+		color := h.paint.theme.Value
+		if attr.Key == h.keys.Error {
+			color = h.paint.theme.Err
+		}
+		buf.WriteByte(' ')
+		buf.WriteString(h.paint.paint(quoteIfNeeded(prefix+attr.Key)+"=", h.paint.theme.Key))
+		buf.WriteString(h.paint.paint(quoteIfNeeded(tintValue(attr.Value)), color))
+	}
+}
+
+// tintValue returns the text of the value in the same way as tint.
+func tintValue(value slog.Value) string {
+	if value.Kind() == slog.KindAny {
+		if marshaler, ok := value.Any().(encoding.TextMarshaler); ok {
+			if data, err := marshaler.MarshalText(); err == nil {
+				return string(data)
+			}
+		}
+	}
+	return value.String()
+}
@@ -1,9 +1,12 @@
 package corelog
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"log/slog"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -36,23 +39,23 @@ func (h *TestHandler) WithGroup(name string) slog.Handler {
 }
 
 func TestHandlerWithLevelInfo(t *testing.T) {
-	SetConfig(Config{Level: LevelInfo})
-	handler := namedHandler{name: "test"}
+	resetConfig(t, Config{Level: LevelInfo})
+	handler := namedHandler{name: "test", registry: defaultRegistry}
 
 	assert.True(t, handler.Enabled(context.Background(), slog.LevelInfo))
 	assert.True(t, handler.Enabled(context.Background(), slog.LevelError))
 }
 
 func TestHandlerWithLevelError(t *testing.T) {
-	SetConfig(Config{Level: LevelError})
-	handler := namedHandler{name: "test"}
+	resetConfig(t, Config{Level: LevelError})
+	handler := namedHandler{name: "test", registry: defaultRegistry}
 
 	assert.False(t, handler.Enabled(context.Background(), slog.LevelInfo))
 	assert.True(t, handler.Enabled(context.Background(), slog.LevelError))
 }
 
 func TestHandlerWithAttrs(t *testing.T) {
-	handler := namedHandler{name: "test"}
+	handler := namedHandler{name: "test", registry: defaultRegistry}
 	attrs := []slog.Attr{slog.Any("extra", "value")}
 	other := handler.WithAttrs(attrs)
 
@@ -64,7 +67,7 @@ func TestHandlerWithAttrs(t *testing.T) {
 }
 
 func TestHandlerWithGroup(t *testing.T) {
-	handler := namedHandler{name: "test"}
+	handler := namedHandler{name: "test", registry: defaultRegistry}
 	other := handler.WithGroup("group")
 
 	otherHandler, ok := other.(*namedHandler)
@@ -73,3 +76,131 @@ func TestHandlerWithGroup(t *testing.T) {
 	assert.Equal(t, "group", otherHandler.group)
 	assert.Equal(t, []slog.Attr(nil), otherHandler.attrs)
 }
+
+func TestJSONHandlerWithKeys(t *testing.T) {
+	var buf bytes.Buffer
+	config := Config{Keys: Keys{Time: "ts", Level: "severity", Message: "message"}}
+	handler := newJSONHandler(config, "test", &buf)
+
+	record := slog.NewRecord(time.Now(), slog.LevelError, "test", 0)
+	require.NoError(t, handler.Handle(context.Background(), record))
+
+	var values map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &values))
+	assert.Contains(t, values, "ts")
+	assert.Equal(t, "ERROR", values["severity"])
+	assert.Equal(t, "test", values["message"])
+}
+
+func TestJSONHandlerWithTimeFormat(t *testing.T) {
+	var buf bytes.Buffer
+	config := Config{TimeFormat: TimeFormatUnixMilli}
+	handler := newJSONHandler(config, "test", &buf)
+
+	now := time.Now()
+	record := slog.NewRecord(now, slog.LevelInfo, "test", 0)
+	require.NoError(t, handler.Handle(context.Background(), record))
+
+	var values map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &values))
+	assert.Equal(t, float64(now.UnixMilli()), values[timeKey])
+}
+
+func TestJSONHandlerWithTimeFormatNone(t *testing.T) {
+	var buf bytes.Buffer
+	config := Config{TimeFormat: TimeFormatNone}
+	handler := newJSONHandler(config, "test", &buf)
+
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, "test", 0)
+	require.NoError(t, handler.Handle(context.Background(), record))
+
+	var values map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &values))
+	assert.NotContains(t, values, timeKey)
+}
+
+func TestTintHandlerWithTimeFormat(t *testing.T) {
+	var buf bytes.Buffer
+	config := Config{TimeFormat: "2006", TimeZone: TimeZoneUTC}
+	handler := newTintHandler(config, "test", &buf)
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	record := slog.NewRecord(now, slog.LevelInfo, "message", 0)
+	require.NoError(t, handler.Handle(context.Background(), record))
+	assert.Equal(t, "2024 INF test message\n", buf.String())
+}
+
+func TestTintHandlerWithThemeColors(t *testing.T) {
+	t.Setenv("FORCE_COLOR", "1")
+	var buf bytes.Buffer
+	handler := newTintHandler(Config{TimeFormat: TimeFormatNone}, "test", &buf).WithGroup("group")
+
+	record := slog.NewRecord(time.Time{}, slog.LevelInfo, "message", 0)
+	record.AddAttrs(slog.String("key", "a value"), slog.String(errorKey, "failed"))
+	require.NoError(t, handler.Handle(context.Background(), record))
+	assert.Contains(t, buf.String(), "message \033[36mgroup.key=\033[0m\033[97m\"a value\"\033[0m")
+	assert.Contains(t, buf.String(), "\033[36mgroup.$err=\033[0m\033[31mfailed\033[0m\n")
+}
+
+func TestTintHandlerWithoutColors(t *testing.T) {
+	var buf bytes.Buffer
+	handler := newTintHandler(Config{TimeFormat: TimeFormatNone, DisableColor: true}, "test", &buf)
+
+	record := slog.NewRecord(time.Time{}, slog.LevelInfo, "message", 0)
+	record.AddAttrs(slog.String(nameKey, "test"), slog.Int("count", 1), slog.String("empty", ""), slog.Group("group", slog.Bool("ok", true)))
+	require.NoError(t, handler.WithAttrs([]slog.Attr{slog.String("static", "value")}).Handle(context.Background(), record))
+	assert.Equal(t, "INF test message static=value count=1 empty=\"\" group.ok=true\n", buf.String())
+}
+
+func TestHandlerWithConfigAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	registry := NewRegistry(Config{Format: FormatJSON, TimeFormat: TimeFormatNone, Attrs: map[string]string{"service": "defradb"}})
+	registry.SetOutput(OutputStderr, &buf)
+	registry.SetConfigOverrides("node,attr.node_id=1")
+
+	registry.NewLogger("node").WithGroup("group").Info("message", String("key", "value"))
+	registry.NewLogger("other").Info("message")
+
+	assert.Equal(t, `{"$level":"INFO","$msg":"message","node_id":"1","service":"defradb","group":{"$name":"node","key":"value"}}`+"\n"+
+		`{"$level":"INFO","$msg":"message","service":"defradb","$name":"other"}`+"\n", buf.String())
+}
+
+func TestHandlerCache(t *testing.T) {
+	var output bytes.Buffer
+	registry := NewRegistry(Config{Format: FormatJSON, TimeFormat: TimeFormatNone})
+	registry.SetOutput(OutputStderr, &output)
+
+	log := registry.NewLogger("cache")
+	cache := log.handler.(*namedHandler).cache
+	log.Info("first")
+	first := cache.Load()
+	require.NotNil(t, first)
+	log.Info("second")
+	assert.Same(t, first, cache.Load())
+
+	registry.SetConfigOverride("cache", Config{Format: FormatText, DisableColor: true})
+	log.Info("third")
+	second := cache.Load()
+	assert.NotSame(t, first, second)
+	assert.Contains(t, output.String(), "INF cache third\n")
+
+	registry.SetOutput(OutputStderr, &output)
+	log.Info("fourth")
+	assert.NotSame(t, second, cache.Load())
+}
+
+func TestHandlerCacheWithAttrs(t *testing.T) {
+	var output bytes.Buffer
+	registry := NewRegistry(Config{Format: FormatJSON, TimeFormat: TimeFormatNone})
+	registry.SetOutput(OutputStderr, &output)
+
+	log := registry.NewLogger("cache")
+	log.Info("first")
+	log.WithAttrs(slog.String("key", "value")).Info("second")
+	log.Info("third")
+
+	assert.Equal(t, `{"$level":"INFO","$msg":"first","$name":"cache"}
+{"$level":"INFO","$msg":"second","key":"value","$name":"cache"}
+{"$level":"INFO","$msg":"third","$name":"cache"}
+`, output.String())
+}
@@ -0,0 +1,170 @@
+package corelog
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+const (
+	// nameKey is the key for the logger name attribute
+	nameKey = "$name"
+	// stackKey is the key for the logger stack attribute
+	stackKey = "$stack"
+	// errorKey is the key for the logger error attribute
+	errorKey = "$err"
+	// msgKey is the key for the logger message attribute
+	msgKey = "$msg"
+	// timeKey is the key for the logger time attribute
+	timeKey = "$time"
+	// levelKey is the key for the logger level attribute
+	levelKey = "$level"
+	// sourceKey is the key for the logger source attribute
+	sourceKey = "$source"
+)
+
+const (
+	// KeysDefault specifies the default $-prefixed key names.
+	KeysDefault = "default"
+	// KeysSlog specifies the standard slog key names.
+	KeysSlog = "slog"
+)
+
+// Keys contains the attribute key names used by structured formats.
+//
+// Empty values default to the corresponding value from DefaultKeys.
+type Keys struct {
+	// Time is the key for the record time.
+	Time string
+	// Level is the key for the record level.
+	Level string
+	// Message is the key for the record message.
+	Message string
+	// Source is the key for the record source location.
+	Source string
+	// Name is the key for the logger name.
+	Name string
+	// Error is the key for the error message.
+	Error string
+	// Stack is the key for the error stack trace.
+	Stack string
+}
+
+// DefaultKeys returns the default $-prefixed key names.
+func DefaultKeys() Keys {
+	return Keys{
+		Time:    timeKey,
+		Level:   levelKey,
+		Message: msgKey,
+		Source:  sourceKey,
+		Name:    nameKey,
+		Error:   errorKey,
+		Stack:   stackKey,
+	}
+}
+
+// SlogKeys returns the standard slog key names.
+//
+// Keys that have no slog equivalent use plain names without a prefix.
+func SlogKeys() Keys {
+	return Keys{
+		Time:    slog.TimeKey,
+		Level:   slog.LevelKey,
+		Message: slog.MessageKey,
+		Source:  slog.SourceKey,
+		Name:    "logger",
+		Error:   "error",
+		Stack:   "stack",
+	}
+}
+
+// withDefaults returns a copy of k with empty values set to the default key names.
+func (k Keys) withDefaults() Keys {
+	defaults := DefaultKeys()
+	if k.Time == "" {
+		k.Time = defaults.Time
+	}
+	if k.Level == "" {
+		k.Level = defaults.Level
+	}
+	if k.Message == "" {
+		k.Message = defaults.Message
+	}
+	if k.Source == "" {
+		k.Source = defaults.Source
+	}
+	if k.Name == "" {
+		k.Name = defaults.Name
+	}
+	if k.Error == "" {
+		k.Error = defaults.Error
+	}
+	if k.Stack == "" {
+		k.Stack = defaults.Stack
+	}
+	return k
+}
+
+// set sets the key name for the given field or preset and
+// returns false if the field or preset is not valid.
+//
+// Valid fields are time, level, msg, source, name, err, and stack.
+// Valid presets are default and slog.
+func (k *Keys) set(field string, value string) bool {
+	switch strings.ToLower(field) {
+	case "time":
+		k.Time = value
+	case "level":
+		k.Level = value
+	case "msg":
+		k.Message = value
+	case "source":
+		k.Source = value
+	case "name":
+		k.Name = value
+	case "err":
+		k.Error = value
+	case "stack":
+		k.Stack = value
+	default:
+		return false
+	}
+	return true
+}
+
+// setPreset sets all key names from the given preset and
+// returns false if the preset is not valid.
+func (k *Keys) setPreset(preset string) bool {
+	switch strings.ToLower(preset) {
+	case KeysDefault:
+		*k = DefaultKeys()
+	case KeysSlog:
+		*k = SlogKeys()
+	default:
+		return false
+	}
+	return true
+}
+
+// parseKeys parses key names from the given text and sets them on the keys.
+//
+// Values are comma separated, where each value is either a preset
+// or a field and key name pair separated by "=". Invalid values
+// are skipped and returned as an error.
+func parseKeys(text string, keys Keys) (Keys, error) {
+	var errs []error
+	for _, part := range strings.Split(text, ",") {
+		values := strings.SplitN(part, "=", 2)
+		if len(values) != 2 {
+			if !keys.setPreset(strings.TrimSpace(part)) {
+				errs = append(errs, fmt.Errorf("invalid keys preset %q", strings.TrimSpace(part)))
+			}
+			continue
+		}
+		if !keys.set(strings.TrimSpace(values[0]), strings.TrimSpace(values[1])) {
+			errs = append(errs, fmt.Errorf("invalid keys field %q", strings.TrimSpace(values[0])))
+		}
+	}
+	return keys, errors.Join(errs...)
+}
@@ -0,0 +1,43 @@
+package corelog
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeysWithDefaults(t *testing.T) {
+	keys := Keys{Time: "ts", Name: "logger"}.withDefaults()
+	assert.Equal(t, "ts", keys.Time)
+	assert.Equal(t, levelKey, keys.Level)
+	assert.Equal(t, msgKey, keys.Message)
+	assert.Equal(t, sourceKey, keys.Source)
+	assert.Equal(t, "logger", keys.Name)
+	assert.Equal(t, errorKey, keys.Error)
+	assert.Equal(t, stackKey, keys.Stack)
+}
+
+func TestParseKeys(t *testing.T) {
+	keys, err := parseKeys("time=ts,level=severity,name=logger,err=error,invalid=value", Keys{})
+	assert.Equal(t, Keys{Time: "ts", Level: "severity", Name: "logger", Error: "error"}, keys)
+	assert.EqualError(t, err, `invalid keys field "invalid"`)
+}
+
+func TestParseKeysWithPreset(t *testing.T) {
+	keys, err := parseKeys("slog,stack=trace", Keys{})
+	require.NoError(t, err)
+	assert.Equal(t, slog.TimeKey, keys.Time)
+	assert.Equal(t, slog.LevelKey, keys.Level)
+	assert.Equal(t, slog.MessageKey, keys.Message)
+	assert.Equal(t, slog.SourceKey, keys.Source)
+	assert.Equal(t, "trace", keys.Stack)
+
+	keys, err = parseKeys("default", keys)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultKeys(), keys)
+
+	_, err = parseKeys("zap", keys)
+	assert.EqualError(t, err, `invalid keys preset "zap"`)
+}
@@ -3,12 +3,24 @@ package corelog
 import "log/slog"
 
 // namedLeveler is an slog.Leveler that gets its value from a named config.
-type namedLeveler string
+type namedLeveler struct {
+	registry *Registry
+	name     string
+}
 
 func (n namedLeveler) Level() slog.Level {
-	switch cfg := GetConfig(string(n)); cfg.Level {
+	return configLevel(n.registry.GetConfig(n.name))
+}
+
+// configLevel returns the slog level of the config.
+func configLevel(config Config) slog.Level {
+	switch config.Level {
+	case LevelDebug:
+		return slog.LevelDebug
 	case LevelInfo:
 		return slog.LevelInfo
+	case LevelWarn:
+		return slog.LevelWarn
 	case LevelError:
 		return slog.LevelError
 	default:
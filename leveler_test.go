@@ -8,12 +8,30 @@ import (
 )
 
 func TestNamedLeveler(t *testing.T) {
-	leveler := namedLeveler("core")
+	restoreConfig(t)
+	SetConfig(Config{})
+
+	leveler := namedLeveler{registry: defaultRegistry, name: "core"}
 	assert.Equal(t, slog.LevelInfo, leveler.Level())
 
+	SetConfigOverride("core", Config{Level: LevelDebug})
+	assert.Equal(t, slog.LevelDebug, leveler.Level())
+
 	SetConfigOverride("core", Config{Level: LevelInfo})
 	assert.Equal(t, slog.LevelInfo, leveler.Level())
 
+	SetConfigOverride("core", Config{Level: LevelWarn})
+	assert.Equal(t, slog.LevelWarn, leveler.Level())
+
 	SetConfigOverride("core", Config{Level: LevelError})
 	assert.Equal(t, slog.LevelError, leveler.Level())
 }
+
+func TestNamedLevelerWithParentOverride(t *testing.T) {
+	restoreConfig(t)
+	SetConfig(Config{Level: LevelError})
+	SetConfigOverride("leveler", Config{Level: LevelDebug})
+
+	assert.Equal(t, slog.LevelDebug, namedLeveler{registry: defaultRegistry, name: "leveler.child"}.Level())
+	assert.Equal(t, slog.LevelError, namedLeveler{registry: defaultRegistry, name: "leveler2"}.Level())
+}
@@ -10,24 +10,35 @@ import (
 
 // Logger is a logger that wraps the slog package.
 type Logger struct {
-	name    string
-	handler slog.Handler
+	name     string
+	registry *Registry
+	handler  slog.Handler
 }
 
-// NewLogger returns a new named logger.
+// NewLogger returns a new named logger that uses the config of the default registry.
 func NewLogger(name string) *Logger {
-	return &Logger{
-		name:    name,
-		handler: &namedHandler{name: name},
+	return defaultRegistry.NewLogger(name)
+}
+
+// Named returns a new logger with the given name appended to the receiver's
+// name and separated by ".", so that the config overrides of the receiver
+// also apply to the returned logger.
+//
+// The returned logger does not have the attributes and groups of the receiver.
+func (l *Logger) Named(name string) *Logger {
+	if l.name == "" {
+		return l.registry.NewLogger(name)
 	}
+	return l.registry.NewLogger(l.name + "." + name)
 }
 
 // WithAttrs returns a new Logger whose attributes consist of
 // both the receiver's attributes and the arguments.
 func (l *Logger) WithAttrs(attrs ...slog.Attr) *Logger {
 	return &Logger{
-		name:    l.name,
-		handler: l.handler.WithAttrs(attrs),
+		name:     l.name,
+		registry: l.registry,
+		handler:  l.handler.WithAttrs(attrs),
 	}
 }
 
@@ -35,11 +46,22 @@ func (l *Logger) WithAttrs(attrs ...slog.Attr) *Logger {
 // the receiver's existing groups.
 func (l *Logger) WithGroup(name string) *Logger {
 	return &Logger{
-		name:    l.name,
-		handler: l.handler.WithGroup(name),
+		name:     l.name,
+		registry: l.registry,
+		handler:  l.handler.WithGroup(name),
 	}
 }
 
+// Debug logs a message at debug log level.
+func (l *Logger) Debug(msg string, args ...slog.Attr) {
+	l.log(context.Background(), slog.LevelDebug, nil, msg, args)
+}
+
+// DebugContext logs a message at debug log level.
+func (l *Logger) DebugContext(ctx context.Context, msg string, args ...slog.Attr) {
+	l.log(ctx, slog.LevelDebug, nil, msg, args)
+}
+
 // Info logs a message at info log level.
 func (l *Logger) Info(msg string, args ...slog.Attr) {
 	l.log(context.Background(), slog.LevelInfo, nil, msg, args)
@@ -50,6 +72,16 @@ func (l *Logger) InfoContext(ctx context.Context, msg string, args ...slog.Attr)
 	l.log(ctx, slog.LevelInfo, nil, msg, args)
 }
 
+// Warn logs a message at warn log level.
+func (l *Logger) Warn(msg string, args ...slog.Attr) {
+	l.log(context.Background(), slog.LevelWarn, nil, msg, args)
+}
+
+// WarnContext logs a message at warn log level.
+func (l *Logger) WarnContext(ctx context.Context, msg string, args ...slog.Attr) {
+	l.log(ctx, slog.LevelWarn, nil, msg, args)
+}
+
 // Error logs a message at error log level.
 func (l *Logger) Error(msg string, args ...slog.Attr) {
 	l.log(context.Background(), slog.LevelError, nil, msg, args)
@@ -70,6 +102,15 @@ func (l *Logger) ErrorContextE(ctx context.Context, msg string, err error, args
 	l.log(ctx, slog.LevelError, err, msg, args)
 }
 
+// config returns the config of the cached handler of the logger,
+// or the config of the registry if the handler is not a named handler.
+func (l *Logger) config() Config {
+	if handler, ok := l.handler.(*namedHandler); ok {
+		return handler.config()
+	}
+	return l.registry.GetConfig(l.name)
+}
+
 // log wraps calls to the underlying logger so that the caller source can be corrected and
 // an optional stacktrace can be included.
 func (l *Logger) log(ctx context.Context, level slog.Level, err error, msg string, args []slog.Attr) {
@@ -77,29 +118,35 @@ func (l *Logger) log(ctx context.Context, level slog.Level, err error, msg strin
 	if !l.handler.Enabled(ctx, level) {
 		return
 	}
+	l.handle(ctx, level, err, msg, args)
+}
 
+// handle writes a record to the handler without checking if the level is enabled.
+func (l *Logger) handle(ctx context.Context, level slog.Level, err error, msg string, args []slog.Attr) {
 	// use latest config values
-	config := GetConfig(l.name)
+	config := l.config()
 
 	var pcs [1]uintptr
 	// add caller source if enabled
 	if config.EnableSource {
-		runtime.Callers(3, pcs[:]) // skip [Callers, log, Info]
+		runtime.Callers(4, pcs[:]) // skip [Callers, handle, log, Info]
 	}
 
+	keys := config.Keys.withDefaults()
+
 	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
 	// add logger name
-	r.Add(nameKey, l.name)
+	r.Add(keys.Name, l.name)
 	// add remaining attributes
 	r.AddAttrs(args...)
 
 	// add stack trace if enabled
 	if err != nil && config.EnableStackTrace {
-		r.Add(stackKey, fmt.Sprintf("%+v", err))
+		r.Add(keys.Stack, fmt.Sprintf("%+v", err))
 	}
 	// add error if not nil
 	if err != nil {
-		r.Add(errorKey, err.Error())
+		r.Add(keys.Error, err.Error())
 	}
 
 	_ = l.handler.Handle(ctx, r)
@@ -1,10 +1,13 @@
 package corelog
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"log/slog"
+	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -17,7 +20,7 @@ func TestNewLogger(t *testing.T) {
 }
 
 func TestLoggerLogWithConfigOverride(t *testing.T) {
-	SetConfig(Config{
+	resetConfig(t, Config{
 		Level:            LevelError,
 		Format:           FormatJSON,
 		Output:           OutputStderr,
@@ -58,12 +61,13 @@ func TestLoggerLogWithConfigOverride(t *testing.T) {
 }
 
 func TestLoggerInfoWithEnableSource(t *testing.T) {
-	SetConfig(Config{EnableSource: true})
+	resetConfig(t, Config{EnableSource: true})
 
 	handler := &TestHandler{}
 	logger := &Logger{
-		name:    "test",
-		handler: handler,
+		name:     "test",
+		registry: defaultRegistry,
+		handler:  handler,
 	}
 
 	logger.Info("test", String("arg1", "val1"))
@@ -73,6 +77,9 @@ func TestLoggerInfoWithEnableSource(t *testing.T) {
 	assert.Equal(t, "test", handler.records[0].Message)
 	assert.NotEqual(t, uintptr(0x00), handler.records[0].PC)
 
+	frame, _ := runtime.CallersFrames([]uintptr{handler.records[0].PC}).Next()
+	assert.Equal(t, "logger_test.go", filepath.Base(frame.File))
+
 	attrs := []slog.Attr{
 		slog.Any(nameKey, "test"),
 		slog.Any("arg1", "val1"),
@@ -80,10 +87,68 @@ func TestLoggerInfoWithEnableSource(t *testing.T) {
 	assertRecordAttrs(t, handler.records[0], attrs...)
 }
 
+func TestLoggerDebug(t *testing.T) {
+	handler := &TestHandler{level: slog.LevelDebug}
+	logger := &Logger{
+		name:     "debug",
+		registry: defaultRegistry,
+		handler:  handler,
+	}
+
+	logger.Debug("test", String("arg1", "val1"))
+	require.Len(t, handler.records, 1)
+
+	assert.Equal(t, slog.LevelDebug, handler.records[0].Level)
+	assert.Equal(t, "test", handler.records[0].Message)
+}
+
+func TestLoggerWarn(t *testing.T) {
+	handler := &TestHandler{}
+	logger := &Logger{
+		name:     "warn",
+		registry: defaultRegistry,
+		handler:  handler,
+	}
+
+	logger.Warn("test", String("arg1", "val1"))
+	require.Len(t, handler.records, 1)
+
+	assert.Equal(t, slog.LevelWarn, handler.records[0].Level)
+	assert.Equal(t, "test", handler.records[0].Message)
+}
+
+func TestLoggerErrorEWithKeys(t *testing.T) {
+	resetConfig(t, Config{Keys: Keys{Name: "logger", Error: "error"}})
+
+	handler := &TestHandler{}
+	logger := &Logger{
+		name:     "keys",
+		registry: defaultRegistry,
+		handler:  handler,
+	}
+
+	err := errors.New("test error")
+	logger.ErrorE("test", err)
+	require.Len(t, handler.records, 1)
+
+	attrs := []slog.Attr{
+		slog.Any("logger", "keys"),
+		slog.Any("error", err.Error()),
+	}
+	assertRecordAttrs(t, handler.records[0], attrs...)
+}
+
+func TestLoggerNamed(t *testing.T) {
+	assert.Equal(t, "net.p2p", NewLogger("net").Named("p2p").name)
+	assert.Equal(t, "net.p2p.dht", NewLogger("net").Named("p2p").Named("dht").name)
+	assert.Equal(t, "p2p", NewLogger("").Named("p2p").name)
+}
+
 func TestLoggerWithAttrs(t *testing.T) {
 	handler := &TestHandler{}
 	logger := &Logger{
-		handler: handler,
+		registry: defaultRegistry,
+		handler:  handler,
 	}
 
 	attrs := []slog.Attr{slog.Any("extra", "value")}
@@ -97,7 +162,8 @@ func TestLoggerWithAttrs(t *testing.T) {
 func TestLoggerWithGroup(t *testing.T) {
 	handler := &TestHandler{}
 	logger := &Logger{
-		handler: handler,
+		registry: defaultRegistry,
+		handler:  handler,
 	}
 
 	other := logger.WithGroup("group")
@@ -120,3 +186,18 @@ func assertRecordAttrs(
 	})
 	assert.Equal(t, expected, actual)
 }
+
+func TestLoggerUsesCachedConfig(t *testing.T) {
+	var output bytes.Buffer
+	registry := NewRegistry(Config{Format: FormatJSON, TimeFormat: TimeFormatNone})
+	registry.SetOutput(OutputStderr, &output)
+	logger := registry.NewLogger("cache")
+
+	logger.Info("first")
+	registry.SetConfigOverrides("cache,key.name=logger")
+	logger.Info("second")
+
+	assert.Equal(t, `{"$level":"INFO","$msg":"first","$name":"cache"}
+{"$level":"INFO","$msg":"second","logger":"cache"}
+`, output.String())
+}
@@ -0,0 +1,21 @@
+// Package pflag registers the corelog config flags on spf13/pflag flag sets,
+// which are also used by cobra commands, so that the corelog package does not
+// depend on pflag.
+package pflag
+
+import (
+	"flag"
+
+	"github.com/sourcenetwork/corelog"
+	"github.com/spf13/pflag"
+)
+
+// BindFlags registers the flags of corelog.BindFlags on the pflag flag set,
+// e.g. --log-level and --log-overrides.
+//
+// Boolean flags can be set without a value, e.g. --log-source.
+func BindFlags(fs *pflag.FlagSet) {
+	flags := flag.NewFlagSet("corelog", flag.ContinueOnError)
+	corelog.BindFlags(flags)
+	fs.AddGoFlagSet(flags)
+}
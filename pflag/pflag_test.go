@@ -0,0 +1,30 @@
+package pflag
+
+import (
+	"testing"
+
+	"github.com/sourcenetwork/corelog"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindFlags(t *testing.T) {
+	snapshot := corelog.Snapshot()
+	t.Cleanup(func() { corelog.Restore(snapshot) })
+	corelog.SetConfig(corelog.Config{Level: corelog.LevelInfo})
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	BindFlags(fs)
+
+	err := fs.Parse([]string{"--log-level", "warn", "--log-no-color", "--log-keys=slog", "--log-attrs=service=node", "--log-overrides=flags.pflag,format=json"})
+	require.NoError(t, err)
+
+	assert.Equal(t, corelog.LevelWarn, corelog.GetConfig("").Level)
+	assert.Equal(t, true, corelog.GetConfig("").DisableColor)
+	assert.Equal(t, corelog.SlogKeys(), corelog.GetConfig("").Keys)
+	assert.Equal(t, map[string]string{"service": "node"}, corelog.GetConfig("").Attrs)
+	assert.Equal(t, corelog.FormatJSON, corelog.GetConfig("flags.pflag").Format)
+	assert.Equal(t, "bool", fs.Lookup("log-no-color").Value.Type())
+	assert.Equal(t, "sets the logging level (debug, info, warn, error)", fs.Lookup("log-level").Usage)
+}
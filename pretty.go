@@ -0,0 +1,157 @@
+package corelog
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// prettyIndent is the indent for lines written below the message.
+const prettyIndent = "  "
+
+// prettyHandler is an slog.Handler that writes human-friendly multi-line
+// records for development.
+type prettyHandler struct {
+	name   string
+	config Config
+	output io.Writer
+	level  slog.Leveler
+	keys   Keys
+	paint  painter
+	attrs  attrGroups
+}
+
+var _ (slog.Handler) = (*prettyHandler)(nil)
+
+func newPrettyHandler(config Config, name string, output io.Writer) *prettyHandler {
+	return &prettyHandler{
+		name:   name,
+		config: config,
+		output: output,
+		level:  configLevel(config),
+		keys:   config.Keys.withDefaults(),
+		paint:  newPainter(config, detectColor(config, output)),
+	}
+}
+
+func (h *prettyHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *prettyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	other := *h
+	other.attrs = h.attrs.withAttrs(attrs)
+	return &other
+}
+
+func (h *prettyHandler) WithGroup(name string) slog.Handler {
+	other := *h
+	other.attrs = h.attrs.withGroup(name)
+	return &other
+}
+
+func (h *prettyHandler) Handle(ctx context.Context, record slog.Record) error {
+	var buf bytes.Buffer
+
+	// write the header line
+	if !record.Time.IsZero() {
+		if text, ok := timeText(h.config, record.Time.Round(0), time.StampMilli); ok {
+			buf.WriteString(h.paint.paint(text, h.paint.theme.Time))
+			buf.WriteByte(' ')
+		}
+	}
+	buf.WriteString(h.paint.level(record.Level))
+	buf.WriteByte(' ')
+	if h.config.EnableSource && record.PC != 0 {
+		frames := runtime.CallersFrames([]uintptr{record.PC})
+		frame, _ := frames.Next()
+		source := filepath.Base(frame.File) + ":" + strconv.Itoa(frame.Line)
+		buf.WriteString(h.paint.paint(source, h.paint.theme.Source))
+		buf.WriteByte(' ')
+	}
+	if h.name != "" {
+		buf.WriteString(h.paint.name(h.name))
+		buf.WriteByte(' ')
+	}
+	buf.WriteString(record.Message)
+	buf.WriteByte('\n')
+
+	// the name is written in the header and the stack
+	// is written below the attributes
+	var stack string
+	attrs := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		switch attr.Key {
+		case h.keys.Name:
+		case h.keys.Stack:
+			stack = attr.Value.String()
+		default:
+			attrs.AddAttrs(attr)
+		}
+		return true
+	})
+	h.appendAttrs(&buf, h.attrs.resolve(attrs), prettyIndent)
+	if stack != "" {
+		h.appendStack(&buf, stack)
+	}
+
+	_, err := h.output.Write(buf.Bytes())
+	return err
+}
+
+// appendAttrs writes the attributes as a tree with one attribute per line.
+func (h *prettyHandler) appendAttrs(buf *bytes.Buffer, attrs []slog.Attr, indent string) {
+	for i, attr := range attrs {
+		branch, next := "├─ ", "│  "
+		if i == len(attrs)-1 {
+			branch, next = "└─ ", "   "
+		}
+		buf.WriteString(indent)
+		buf.WriteString(h.paint.paint(branch, h.paint.theme.Tree))
+		if attr.Value.Kind() == slog.KindGroup {
+			buf.WriteString(h.paint.paint(attr.Key, h.paint.theme.Key))
+			buf.WriteByte('\n')
+			h.appendAttrs(buf, attr.Value.Group(), indent+next)
+			continue
+		}
+		value := h.paint.paint(attr.Value.String(), h.paint.theme.Value)
+		if attr.Key == h.keys.Error {
+			value = h.paint.paint(attr.Value.String(), h.paint.theme.Err)
+		}
+		buf.WriteString(h.paint.paint(attr.Key, h.paint.theme.Key))
+		buf.WriteString(": ")
+		// indent continuation lines of multi-line values
+		buf.WriteString(strings.ReplaceAll(value, "\n", "\n"+indent+next))
+		buf.WriteByte('\n')
+	}
+}
+
+// appendStack writes the stack trace with one frame per line.
+//
+// Lines that start with whitespace are the file locations of the
+// preceding function and are written dimmed.
+func (h *prettyHandler) appendStack(buf *bytes.Buffer, stack string) {
+	buf.WriteString(prettyIndent)
+	buf.WriteString(h.paint.paint("stack", h.paint.theme.Error))
+	buf.WriteByte('\n')
+	for _, line := range strings.Split(strings.TrimRight(stack, "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		buf.WriteString(prettyIndent + prettyIndent)
+		if line != strings.TrimLeft(line, " \t") {
+			buf.WriteString(prettyIndent + prettyIndent)
+			buf.WriteString(h.paint.paint(trimmed, h.paint.theme.Frame))
+		} else {
+			buf.WriteString(h.paint.paint(trimmed, h.paint.theme.Stack))
+		}
+		buf.WriteByte('\n')
+	}
+}
@@ -0,0 +1,58 @@
+package corelog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrettyHandler(t *testing.T) {
+	var buf bytes.Buffer
+	config := Config{TimeFormat: "15:04:05", TimeZone: TimeZoneUTC}
+	handler := newPrettyHandler(config, "test", &buf).
+		WithAttrs([]slog.Attr{slog.String("outer", "value")}).
+		WithGroup("group")
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	record := slog.NewRecord(now, slog.LevelError, "message", 0)
+	record.AddAttrs(
+		slog.String(nameKey, "test"),
+		slog.Int("a", 1),
+		slog.Group("nested", slog.Bool("b", true)),
+	)
+	require.NoError(t, handler.Handle(context.Background(), record))
+
+	expected := "03:04:05 ERR test message\n" +
+		"  ├─ outer: value\n" +
+		"  └─ group\n" +
+		"     ├─ a: 1\n" +
+		"     └─ nested\n" +
+		"        └─ b: true\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestPrettyHandlerWithStack(t *testing.T) {
+	var buf bytes.Buffer
+	config := Config{TimeFormat: TimeFormatNone}
+	handler := newPrettyHandler(config, "test", &buf)
+
+	record := slog.NewRecord(time.Now(), slog.LevelError, "message", 0)
+	record.AddAttrs(
+		slog.String(stackKey, "test error\nmain.main\n\t/src/main.go:10\n"),
+		slog.String(errorKey, "test error"),
+	)
+	require.NoError(t, handler.Handle(context.Background(), record))
+
+	expected := "ERR test message\n" +
+		"  └─ $err: test error\n" +
+		"  stack\n" +
+		"    test error\n" +
+		"    main.main\n" +
+		"        /src/main.go:10\n"
+	assert.Equal(t, expected, buf.String())
+}
@@ -0,0 +1,154 @@
+package corelog
+
+import (
+	"io"
+	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// defaultRegistry contains the config of the loggers
+	// returned by NewLogger and the package functions.
+	defaultRegistry = NewRegistry(Config{})
+	// writers contains the locked writer of every output writer, so that
+	// records of registries that share a writer are not interleaved
+	writers = map[io.Writer]*lockedWriter{
+		// stdout and stderr are never removed
+		os.Stdout: {writer: os.Stdout, refs: 1},
+		os.Stderr: {writer: os.Stderr, refs: 1},
+	}
+	writersMutex sync.Mutex
+	// registrations is incremented for every registered format or
+	// theme, so that cached handlers of all registries can be rebuilt
+	registrations atomic.Uint64
+)
+
+// Registry contains the config, config overrides, and outputs of a set
+// of loggers, so that loggers of different registries can be configured
+// independently, e.g. for multiple nodes in one process.
+//
+// The package functions such as NewLogger, SetConfig, and SetConfigOverride
+// use the default registry, which is configured from environment variables.
+type Registry struct {
+	mutex sync.RWMutex
+	state configState
+	// version is incremented for every change of the config
+	// or outputs, so that cached handlers can be rebuilt
+	version atomic.Uint64
+
+	outputsMutex sync.RWMutex
+	outputs      map[string]*lockedWriter
+
+	subscribersMutex sync.RWMutex
+	subscribers      []subscriber
+	subscriberID     uint64
+}
+
+// NewRegistry returns a registry with the given config for all loggers and
+// without config overrides that writes to os.Stdout and os.Stderr.
+func NewRegistry(config Config) *Registry {
+	return &Registry{
+		state: configState{
+			config:    config.clone(),
+			overrides: make(map[string]ConfigOverride),
+		},
+		outputs: map[string]*lockedWriter{
+			OutputStdout: acquireWriter(os.Stdout),
+			OutputStderr: acquireWriter(os.Stderr),
+		},
+	}
+}
+
+// NewLogger returns a new named logger that uses the config of the registry.
+func (r *Registry) NewLogger(name string) *Logger {
+	return &Logger{
+		name:     name,
+		registry: r,
+		handler:  &namedHandler{name: name, registry: r, cache: new(atomic.Pointer[cachedHandler])},
+	}
+}
+
+// SetOutput sets the writer of the output with the given name, which is
+// OutputStdout or OutputStderr, for all loggers of the registry.
+//
+// Records are written to the writer with a single call to Write, and
+// writes of all loggers of all registries that use the same writer are
+// serialized.
+func (r *Registry) SetOutput(output string, writer io.Writer) {
+	locked := acquireWriter(writer)
+	r.outputsMutex.Lock()
+	previous := r.outputs[output]
+	r.outputs[output] = locked
+	r.outputsMutex.Unlock()
+	r.version.Add(1)
+	releaseWriter(previous)
+}
+
+// getConfig returns the config for a named logger
+// and the version of the registry.
+func (r *Registry) getConfig(name string) (Config, uint64) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.state.get(name), r.version.Load()
+}
+
+// output returns the writer of the output with the given name.
+func (r *Registry) output(output string) io.Writer {
+	r.outputsMutex.RLock()
+	defer r.outputsMutex.RUnlock()
+	if writer, ok := r.outputs[output]; ok {
+		return writer
+	}
+	// default to stderr if no value is set
+	// or the set value is invalid
+	return r.outputs[OutputStderr]
+}
+
+// lockedWriter is an io.Writer that serializes writes
+// so that concurrent records are not interleaved.
+type lockedWriter struct {
+	mutex  sync.Mutex
+	writer io.Writer
+	// refs is the number of outputs that use the
+	// writer while the writers lock is held
+	refs int
+}
+
+// acquireWriter returns the locked writer that is shared by all outputs
+// with the given writer. Writers that cannot be compared get their own lock.
+func acquireWriter(writer io.Writer) *lockedWriter {
+	if writer == nil || !reflect.TypeOf(writer).Comparable() {
+		return &lockedWriter{writer: writer}
+	}
+	writersMutex.Lock()
+	defer writersMutex.Unlock()
+	locked, ok := writers[writer]
+	if !ok {
+		locked = &lockedWriter{writer: writer}
+		writers[writer] = locked
+	}
+	locked.refs++
+	return locked
+}
+
+// releaseWriter removes the shared locked writer
+// once it is no longer used by any output.
+func releaseWriter(locked *lockedWriter) {
+	writersMutex.Lock()
+	defer writersMutex.Unlock()
+	if locked == nil || locked.refs == 0 {
+		return // not shared
+	}
+	locked.refs--
+	if locked.refs == 0 {
+		delete(writers, locked.writer)
+	}
+}
+
+func (w *lockedWriter) Write(data []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.writer.Write(data)
+}
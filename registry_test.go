@@ -0,0 +1,151 @@
+package corelog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryNewLogger(t *testing.T) {
+	var first, second bytes.Buffer
+	one := NewRegistry(Config{Level: LevelInfo, Format: FormatJSON, TimeFormat: TimeFormatNone})
+	one.SetOutput(OutputStderr, &first)
+	two := NewRegistry(Config{Level: LevelError, Format: FormatText, TimeFormat: TimeFormatNone, DisableColor: true})
+	two.SetOutput(OutputStderr, &second)
+
+	one.NewLogger("node").Info("first")
+	two.NewLogger("node").Info("ignored")
+	two.NewLogger("node").Error("second")
+
+	assert.Equal(t, `{"$level":"INFO","$msg":"first","$name":"node"}`+"\n", first.String())
+	assert.Equal(t, "ERR node second\n", second.String())
+}
+
+func TestRegistriesShareWriterLock(t *testing.T) {
+	var output bytes.Buffer
+	one := NewRegistry(Config{Format: FormatJSON, TimeFormat: TimeFormatNone})
+	one.SetOutput(OutputStderr, &output)
+	two := NewRegistry(Config{Format: FormatJSON, TimeFormat: TimeFormatNone})
+	two.SetOutput(OutputStderr, &output)
+	two.SetOutput(OutputStdout, &output)
+	require.Same(t, one.output(OutputStderr), two.output(OutputStderr))
+
+	var wg sync.WaitGroup
+	for _, registry := range []*Registry{one, two} {
+		logger := registry.NewLogger("shared")
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				logger.Info("message")
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 200, strings.Count(output.String(), `{"$level":"INFO","$msg":"message","$name":"shared"}`+"\n"))
+
+	// the lock is removed once no output uses the writer
+	one.SetOutput(OutputStderr, io.Discard)
+	two.SetOutput(OutputStderr, io.Discard)
+	two.SetOutput(OutputStdout, io.Discard)
+	writersMutex.Lock()
+	assert.NotContains(t, writers, io.Writer(&output))
+	writersMutex.Unlock()
+}
+
+func TestRegistrySetOutput(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	registry := NewRegistry(Config{Format: FormatJSON, TimeFormat: TimeFormatNone})
+	registry.SetOutput(OutputStdout, &stdout)
+	registry.SetOutput(OutputStderr, &stderr)
+	registry.SetConfigOverrides("registry.stdout,output=stdout")
+
+	registry.NewLogger("registry.stdout").Info("out")
+	registry.NewLogger("registry.stderr").Info("err")
+
+	assert.Contains(t, stdout.String(), `"$msg":"out"`)
+	assert.Contains(t, stderr.String(), `"$msg":"err"`)
+	assert.NotContains(t, stdout.String(), `"$msg":"err"`)
+}
+
+func TestRegistryIsolation(t *testing.T) {
+	restoreConfig(t)
+	registry := NewRegistry(Config{Level: LevelWarn})
+
+	var notified []string
+	unsubscribe := registry.Subscribe(func(name string, old, new Config) {
+		notified = append(notified, name)
+	})
+	defer unsubscribe()
+
+	SetConfigOverride("registry.isolated", Config{Level: LevelDebug})
+	registry.SetConfigOverride("registry.other", Config{Level: LevelError})
+
+	assert.Equal(t, LevelWarn, registry.GetConfig("registry.isolated").Level)
+	assert.Equal(t, LevelDebug, GetConfig("registry.isolated").Level)
+	assert.NotEqual(t, LevelError, GetConfig("registry.other").Level)
+	assert.Equal(t, []string{"registry.other"}, notified)
+}
+
+func TestRegistryNamedLogger(t *testing.T) {
+	registry := NewRegistry(Config{})
+	logger := registry.NewLogger("net").Named("p2p").WithAttrs(String("key", "value"))
+
+	assert.Equal(t, "net.p2p", logger.name)
+	assert.Same(t, registry, logger.registry)
+}
+
+func TestRegistrySnapshotRestore(t *testing.T) {
+	registry := NewRegistry(Config{Level: LevelInfo})
+	registry.SetConfigOverride("net", Config{Level: LevelDebug})
+
+	other := NewRegistry(Config{})
+	other.Restore(registry.Snapshot())
+	assert.Equal(t, LevelDebug, other.GetConfig("net").Level)
+
+	other.ClearConfigOverrides()
+	assert.Equal(t, LevelInfo, other.GetConfig("net").Level)
+	assert.Equal(t, LevelDebug, registry.GetConfig("net").Level)
+}
+
+func TestRegistryDumpConfig(t *testing.T) {
+	registry := NewRegistry(Config{Level: LevelInfo})
+	registry.SetConfigOverrides("net,level=debug")
+
+	text, err := registry.DumpConfig(FormatText)
+	require.NoError(t, err)
+	assert.Equal(t, "level=info\nnet,level=debug\n", text)
+}
+
+func TestRegistryConcurrentWrites(t *testing.T) {
+	var output bytes.Buffer
+	registry := NewRegistry(Config{Format: FormatJSON, TimeFormat: TimeFormatNone})
+	registry.SetOutput(OutputStderr, &output)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			log := registry.NewLogger(name)
+			for j := 0; j < 100; j++ {
+				log.Info("message", slog.Int("index", j))
+			}
+		}(fmt.Sprintf("node%d", i))
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	require.Len(t, lines, 800)
+	for _, line := range lines {
+		assert.True(t, json.Valid([]byte(line)), line)
+	}
+}
@@ -0,0 +1,128 @@
+package corelog
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// signalAction is the action performed when a signal is received.
+type signalAction int
+
+const (
+	// signalReload reloads the config.
+	signalReload signalAction = iota
+	// signalVerbose changes the global level to the next more verbose level.
+	signalVerbose
+	// signalQuiet changes the global level to the next less verbose level.
+	signalQuiet
+)
+
+// signalLevels contains the levels stepped through by signals
+// from the most verbose to the least verbose level.
+var signalLevels = []string{LevelDebug, LevelInfo, LevelWarn, LevelError}
+
+// HandleSignals installs a signal handler that reloads the config on SIGHUP and
+// changes the global level on SIGUSR1 and SIGUSR2.
+//
+// SIGHUP reloads the config file at the given path, or LOG_CONFIG_FILE if the path
+// is empty, followed by LOG_OVERRIDES. If there is no config file the config is
+// reloaded from the environment. SIGUSR1 changes the global level to the next more
+// verbose level (error, warn, info, debug) and SIGUSR2 changes it to the next less
+// verbose level, so that info is changed to debug and back with SIGUSR1 and SIGUSR2.
+// The level stays at debug and error when there is no next level.
+// Every change is announced by a record from the corelog logger.
+//
+// Signals that are not supported by the platform are ignored.
+// The returned function removes the signal handler.
+func HandleSignals(path string) (stop func()) {
+	if path == "" {
+		_, path = getEnv(envConfigFile)
+	}
+	signals := make(chan os.Signal, 1)
+	for sig := range signalActions {
+		signal.Notify(signals, sig)
+	}
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		for {
+			select {
+			case sig := <-signals:
+				handleSignal(signalActions[sig], sig.String(), path)
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+			<-exited
+		})
+	}
+}
+
+// handleSignal performs the action for the signal with the given name.
+func handleSignal(action signalAction, name string, path string) {
+	switch action {
+	case signalReload:
+		if err := reloadConfig(path); err != nil {
+			internalLogger.Warn("failed to reload config", String("signal", name), String("error", err.Error()))
+			return
+		}
+		internalLogger.Info("config reloaded", String("signal", name), String("path", path))
+	case signalVerbose, signalQuiet:
+		var level string
+		defaultRegistry.change(func() error {
+			level = nextSignalLevel(defaultRegistry.state.config.Level, action)
+			defaultRegistry.state.config.Level = level
+			return nil
+		})
+		// the notice bypasses the level of the
+		// logger so that it is written for all levels
+		internalLogger.handle(context.Background(), slog.LevelInfo, nil,
+			"log level changed", []slog.Attr{String("signal", name), String("level", level)})
+	}
+}
+
+// nextSignalLevel returns the level after the given level for the action.
+//
+// Empty and invalid levels are treated as info, and the most and least
+// verbose levels are kept if there is no next level.
+func nextSignalLevel(level string, action signalAction) string {
+	index := 1
+	for i, value := range signalLevels {
+		if value == level {
+			index = i
+		}
+	}
+	if action == signalVerbose {
+		index = max(index-1, 0)
+	} else {
+		index = min(index+1, len(signalLevels)-1)
+	}
+	return signalLevels[index]
+}
+
+// reloadConfig reloads the config from the config file at the given
+// path or the environment if the path is empty, followed by LOG_OVERRIDES.
+func reloadConfig(path string) error {
+	if path == "" {
+		SetConfig(DefaultConfig())
+		_, overrides := getEnv(envOverrides)
+		SetConfigOverrides(overrides)
+		return nil
+	}
+	config, overrides, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+	replaceEnvConfig(config, overrides)
+	return nil
+}
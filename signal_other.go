@@ -0,0 +1,10 @@
+//go:build !unix
+
+package corelog
+
+import "os"
+
+// signalActions contains the actions for supported signals.
+//
+// SIGHUP, SIGUSR1, and SIGUSR2 are not supported on this platform.
+var signalActions = map[os.Signal]signalAction{}
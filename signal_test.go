@@ -0,0 +1,80 @@
+package corelog
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextSignalLevel(t *testing.T) {
+	assert.Equal(t, LevelDebug, nextSignalLevel(LevelInfo, signalVerbose))
+	assert.Equal(t, LevelInfo, nextSignalLevel(LevelDebug, signalQuiet))
+	assert.Equal(t, LevelDebug, nextSignalLevel(LevelDebug, signalVerbose))
+	assert.Equal(t, LevelError, nextSignalLevel(LevelError, signalQuiet))
+	assert.Equal(t, LevelError, nextSignalLevel(LevelWarn, signalQuiet))
+	assert.Equal(t, LevelDebug, nextSignalLevel("", signalVerbose))
+	assert.Equal(t, LevelWarn, nextSignalLevel("invalid", signalQuiet))
+}
+
+func TestHandleSignalLevel(t *testing.T) {
+	restoreConfig(t)
+	SetConfig(Config{Level: LevelInfo, Output: OutputStdout})
+
+	handleSignal(signalVerbose, "SIGUSR1", "")
+	assert.Equal(t, LevelDebug, GetConfig("").Level)
+
+	handleSignal(signalQuiet, "SIGUSR2", "")
+	assert.Equal(t, LevelInfo, GetConfig("").Level)
+}
+
+func TestHandleSignalLevelNotice(t *testing.T) {
+	resetConfig(t, Config{Level: LevelError, Format: FormatJSON, TimeFormat: TimeFormatNone})
+	var buf bytes.Buffer
+	setOutput(t, OutputStderr, &buf)
+
+	// the notice is written even though info is below the level
+	handleSignal(signalQuiet, "SIGUSR2", "")
+	assert.Equal(t, LevelError, GetConfig("").Level)
+	assert.Contains(t, buf.String(), `{"$level":"INFO","$msg":"log level changed","$name":"corelog","signal":"SIGUSR2"`)
+}
+
+func TestHandleSignalReload(t *testing.T) {
+	restoreConfig(t)
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte(`level = "warn"`), 0o644))
+
+	handleSignal(signalReload, "SIGHUP", path)
+	assert.Equal(t, LevelWarn, GetConfig("").Level)
+
+	require.NoError(t, os.WriteFile(path, []byte(`levle = "error"`), 0o644))
+	handleSignal(signalReload, "SIGHUP", path)
+	assert.Equal(t, LevelWarn, GetConfig("").Level)
+}
+
+func TestHandleSignals(t *testing.T) {
+	if len(signalActions) == 0 {
+		t.Skip("signals are not supported on this platform")
+	}
+	restoreConfig(t)
+	SetConfig(Config{Level: LevelInfo, Output: OutputStdout})
+
+	stop := HandleSignals("")
+	t.Cleanup(stop)
+
+	process, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	for sig, action := range signalActions {
+		if action == signalVerbose {
+			require.NoError(t, process.Signal(sig))
+		}
+	}
+	require.Eventually(t, func() bool {
+		return GetConfig("").Level == LevelDebug
+	}, time.Second, 10*time.Millisecond)
+}
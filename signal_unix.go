@@ -0,0 +1,15 @@
+//go:build unix
+
+package corelog
+
+import (
+	"os"
+	"syscall"
+)
+
+// signalActions contains the actions for supported signals.
+var signalActions = map[os.Signal]signalAction{
+	syscall.SIGHUP:  signalReload,
+	syscall.SIGUSR1: signalVerbose,
+	syscall.SIGUSR2: signalQuiet,
+}
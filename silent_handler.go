@@ -4,29 +4,24 @@ package corelog
 
 import (
 	"context"
+	"io"
 	"log/slog"
-	"os"
+	"sync/atomic"
 )
 
 // This dummies out all of the logging functionality, so that code using the
 // logger will be silent, if the build tag silent is used.
 
-const (
-	nameKey   = "$name"
-	stackKey  = "$stack"
-	errorKey  = "$err"
-	msgKey    = "$msg"
-	timeKey   = "$time"
-	levelKey  = "$level"
-	sourceKey = "$source"
-)
-
 type namedHandler struct {
-	name  string
-	attrs []slog.Attr
-	group string
+	name     string
+	registry *Registry
+	attrs    []slog.Attr
+	group    string
+	cache    *atomic.Pointer[cachedHandler]
 }
 
+type cachedHandler struct{}
+
 func (h namedHandler) Enabled(ctx context.Context, level slog.Level) bool {
 	return false
 }
@@ -39,10 +34,16 @@ func (h namedHandler) WithGroup(name string) slog.Handler {
 func (h namedHandler) Handle(ctx context.Context, record slog.Record) error {
 	return nil
 }
+func (h namedHandler) config() Config {
+	return Config{}
+}
 
-func newTintHandler(_ Config, _ string, _ *os.File) slog.Handler {
+func newHandler(_ Config, _ string, _ io.Writer) slog.Handler {
+	return namedHandler{}
+}
+func newTintHandler(_ Config, _ string, _ io.Writer) slog.Handler {
 	return namedHandler{}
 }
-func newJSONHandler(_ Config, _ string, _ *os.File) *slog.JSONHandler {
+func newJSONHandler(_ Config, _ string, _ io.Writer) *slog.JSONHandler {
 	return slog.NewJSONHandler(nil, nil)
 }
@@ -0,0 +1,119 @@
+package corelog
+
+import (
+	"log/slog"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// subscriber is a function that is called when the config changes.
+type subscriber struct {
+	id uint64
+	fn func(name string, old, new Config)
+}
+
+// Subscribe registers a function that is called when the config of the default registry changes.
+func Subscribe(fn func(name string, old, new Config)) (unsubscribe func()) {
+	return defaultRegistry.Subscribe(fn)
+}
+
+// Subscribe registers a function that is called when the config changes.
+//
+// The function is called with an empty name when the config for all loggers
+// changes, and with the override name for every config override whose config
+// changes, including changes inherited from the global config and parent
+// overrides. It is called after the change is applied, from the goroutine
+// that made the change, and subscribers are called in the order they were
+// added. The returned function removes the subscription.
+func (r *Registry) Subscribe(fn func(name string, old, new Config)) (unsubscribe func()) {
+	r.subscribersMutex.Lock()
+	defer r.subscribersMutex.Unlock()
+
+	r.subscriberID++
+	id := r.subscriberID
+	r.subscribers = append(r.subscribers, subscriber{id: id, fn: fn})
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			r.subscribersMutex.Lock()
+			defer r.subscribersMutex.Unlock()
+			r.subscribers = slices.DeleteFunc(slices.Clone(r.subscribers), func(s subscriber) bool {
+				return s.id == id
+			})
+		})
+	}
+}
+
+// LogConfigChanges subscribes to config changes of the default registry and logs a "config changed"
+// record from the corelog logger that describes the changed fields.
+//
+// The returned function removes the subscription.
+func LogConfigChanges() (unsubscribe func()) {
+	return Subscribe(logConfigChange)
+}
+
+// logConfigChange logs the fields that are different between the configs.
+func logConfigChange(name string, old, new Config) {
+	var changes []any
+	for _, field := range allConfigFields(&old, &new) {
+		if field.get == nil {
+			continue
+		}
+		oldValue, newValue := field.get(&old), field.get(&new)
+		if !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, slog.Group(field.key, slog.Any("old", oldValue), slog.Any("new", newValue)))
+		}
+	}
+	internalLogger.Info("config changed", String("name", name), Group("changes", changes...))
+}
+
+// change runs the change while the registry lock is held and notifies
+// subscribers of all configs that are changed.
+//
+// Subscribers are not notified if the change returns an error.
+func (r *Registry) change(change func() error) error {
+	r.subscribersMutex.RLock()
+	// subscribers are never modified in place so
+	// they can be called without holding the lock
+	notify := r.subscribers
+	r.subscribersMutex.RUnlock()
+
+	type configChange struct {
+		name     string
+		old, new Config
+	}
+	var changes []configChange
+
+	r.mutex.Lock()
+	var before configState
+	if len(notify) > 0 {
+		before = r.state.clone()
+	}
+	err := change()
+	r.version.Add(1)
+	if len(notify) > 0 && err == nil {
+		after := r.state
+		names := map[string]struct{}{"": {}}
+		for name := range before.overrides {
+			names[name] = struct{}{}
+		}
+		for name := range after.overrides {
+			names[name] = struct{}{}
+		}
+		for _, name := range sortedKeys(names) {
+			if old, new := before.get(name), after.get(name); !reflect.DeepEqual(old, new) {
+				changes = append(changes, configChange{name: name, old: old, new: new})
+			}
+		}
+	}
+	r.mutex.Unlock()
+
+	for _, change := range changes {
+		for _, subscriber := range notify {
+			subscriber.fn(change.name, change.old, change.new)
+		}
+	}
+	return err
+}
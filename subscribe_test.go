@@ -0,0 +1,127 @@
+package corelog
+
+import (
+	"bytes"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// configNotification is a call to a config subscriber.
+type configNotification struct {
+	name     string
+	old, new Config
+}
+
+// subscribeConfig records config notifications for the global config
+// and the overrides with the given names until the test ends.
+func subscribeConfig(t *testing.T, names ...string) *[]configNotification {
+	var notifications []configNotification
+	unsubscribe := Subscribe(func(name string, old, new Config) {
+		if name == "" || slices.Contains(names, name) {
+			notifications = append(notifications, configNotification{name, old, new})
+		}
+	})
+	t.Cleanup(unsubscribe)
+	return &notifications
+}
+
+func TestSubscribeSetConfig(t *testing.T) {
+	restoreConfig(t)
+	SetConfig(Config{Level: LevelInfo})
+
+	notifications := subscribeConfig(t)
+	SetConfig(Config{Level: LevelError})
+
+	require.Len(t, *notifications, 1)
+	assert.Equal(t, configNotification{"", Config{Level: LevelInfo}, Config{Level: LevelError}}, (*notifications)[0])
+}
+
+func TestSubscribeSetConfigOverride(t *testing.T) {
+	restoreConfig(t)
+	SetConfig(Config{Level: LevelInfo})
+
+	notifications := subscribeConfig(t, "subscribe.override")
+	SetConfigOverride("subscribe.override", Config{Level: LevelDebug})
+
+	require.Len(t, *notifications, 1)
+	assert.Equal(t, "subscribe.override", (*notifications)[0].name)
+	assert.Equal(t, LevelInfo, (*notifications)[0].old.Level)
+	assert.Equal(t, LevelDebug, (*notifications)[0].new.Level)
+}
+
+func TestSubscribeInheritedChanges(t *testing.T) {
+	restoreConfig(t)
+	SetConfig(Config{Level: LevelInfo})
+	SetConfigOverrides("subscribe.inherited,format=json")
+
+	notifications := subscribeConfig(t, "subscribe.inherited")
+	SetConfig(Config{Level: LevelWarn})
+
+	require.Len(t, *notifications, 2)
+	assert.Equal(t, "", (*notifications)[0].name)
+	assert.Equal(t, "subscribe.inherited", (*notifications)[1].name)
+	assert.Equal(t, Config{Level: LevelInfo, Format: FormatJSON}, (*notifications)[1].old)
+	assert.Equal(t, Config{Level: LevelWarn, Format: FormatJSON}, (*notifications)[1].new)
+}
+
+func TestSubscribeWithoutChanges(t *testing.T) {
+	restoreConfig(t)
+	SetConfig(Config{Level: LevelInfo})
+
+	notifications := subscribeConfig(t)
+	SetConfig(Config{Level: LevelInfo})
+	assert.Empty(t, *notifications)
+}
+
+func TestSubscribeWithInvalidChange(t *testing.T) {
+	restoreConfig(t)
+
+	notifications := subscribeConfig(t, "subscribe.invalid")
+	assert.Error(t, defaultRegistry.updateConfig("subscribe.invalid", map[string]any{"levle": "error"}, false))
+	assert.Empty(t, *notifications)
+}
+
+func TestUnsubscribe(t *testing.T) {
+	restoreConfig(t)
+	SetConfig(Config{Level: LevelInfo})
+
+	var calls int
+	unsubscribe := Subscribe(func(name string, old, new Config) {
+		if name == "" {
+			calls++
+		}
+	})
+	SetConfig(Config{Level: LevelError})
+	unsubscribe()
+	unsubscribe()
+	SetConfig(Config{Level: LevelWarn})
+	assert.Equal(t, 1, calls)
+}
+
+func TestLogConfigChanges(t *testing.T) {
+	resetConfig(t, Config{Level: LevelInfo, Format: FormatJSON, TimeFormat: TimeFormatNone})
+	var buf bytes.Buffer
+	setOutput(t, OutputStderr, &buf)
+
+	unsubscribe := LogConfigChanges()
+	defer unsubscribe()
+	SetConfigOverrides("subscribe.log,level=debug")
+
+	assert.Equal(t, `{"$level":"INFO","$msg":"config changed","$name":"corelog","name":"subscribe.log","changes":{"level":{"old":"info","new":"debug"}}}`+"\n", buf.String())
+}
+
+// setOutput sets the writer of the output of the default
+// registry and restores the previous writer after the test.
+func setOutput(t *testing.T, output string, writer io.Writer) {
+	defaultRegistry.outputsMutex.Lock()
+	previous := defaultRegistry.outputs[output]
+	defaultRegistry.outputsMutex.Unlock()
+	t.Cleanup(func() {
+		defaultRegistry.SetOutput(output, previous.writer)
+	})
+	defaultRegistry.SetOutput(output, writer)
+}
@@ -0,0 +1,216 @@
+package corelog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultTemplate is the layout used by the template format when no template is set.
+const DefaultTemplate = "{{.Time}} {{.Level}} {{.Name}} {{.Msg}} {{.Attrs}}"
+
+// templateRecord contains the values available to a template.
+type templateRecord struct {
+	// Time is the time of the record.
+	Time templateTime
+	// Level is the level of the record.
+	Level slog.Level
+	// Name is the name of the logger.
+	Name string
+	// Msg is the message of the record.
+	Msg string
+	// Attrs contains the attributes of the record.
+	Attrs templateAttrs
+	// Source is the file and line of the caller if source is enabled.
+	Source string
+}
+
+// templateTime is a time that is printed using the config time format.
+type templateTime struct {
+	time.Time
+	text string
+}
+
+func newTemplateTime(config Config, t time.Time) templateTime {
+	if t.IsZero() {
+		return templateTime{}
+	}
+	// default to RFC3339 if no value is set
+	text, _ := timeText(config, t, time.RFC3339)
+	return templateTime{Time: t.In(timeLocation(config.TimeZone)), text: text}
+}
+
+func (t templateTime) String() string {
+	return t.text
+}
+
+// templateAttrs is a list of attributes that is printed as key value pairs.
+type templateAttrs []slog.Attr
+
+func (a templateAttrs) String() string {
+	var buf strings.Builder
+	appendTemplateAttrs(&buf, "", a)
+	return buf.String()
+}
+
+func appendTemplateAttrs(buf *strings.Builder, prefix string, attrs []slog.Attr) {
+	for _, attr := range attrs {
+		if attr.Value.Kind() == slog.KindGroup {
+			appendTemplateAttrs(buf, prefix+attr.Key+".", attr.Value.Group())
+			continue
+		}
+		if buf.Len() > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(quoteIfNeeded(prefix + attr.Key))
+		buf.WriteByte('=')
+		buf.WriteString(quoteIfNeeded(attr.Value.String()))
+	}
+}
+
+// quoteIfNeeded returns the text quoted if it contains spaces,
+// quotes, equals signs, or non printable characters.
+func quoteIfNeeded(text string) string {
+	if text == "" {
+		return `""`
+	}
+	for _, r := range text {
+		if unicode.IsSpace(r) || r == '"' || r == '=' || !unicode.IsPrint(r) {
+			return strconv.Quote(text)
+		}
+	}
+	return text
+}
+
+// templateFuncs returns the helper functions available to templates.
+func templateFuncs(paint painter) template.FuncMap {
+	return template.FuncMap{
+		"pad": func(width int, value any) string {
+			text := fmt.Sprint(value)
+			return text + strings.Repeat(" ", max(0, width-utf8.RuneCountInString(text)))
+		},
+		"padLeft": func(width int, value any) string {
+			text := fmt.Sprint(value)
+			return strings.Repeat(" ", max(0, width-utf8.RuneCountInString(text))) + text
+		},
+		"color": func(name string, value any) string {
+			return paint.paint(fmt.Sprint(value), name)
+		},
+		"colorLevel": func(level slog.Level) string {
+			return paint.paint(level.String(), paint.theme.Level(level))
+		},
+		"colorName": func(name string) string {
+			return paint.name(name)
+		},
+		"colorTime": func(value templateTime) string {
+			return paint.paint(value.String(), paint.theme.Time)
+		},
+		"time": func(layout string, value templateTime) string {
+			return value.Format(layout)
+		},
+		"upper": func(value any) string {
+			return strings.ToUpper(fmt.Sprint(value))
+		},
+		"lower": func(value any) string {
+			return strings.ToLower(fmt.Sprint(value))
+		},
+	}
+}
+
+// parseTemplate parses the given template text, using the
+// default template when the text is empty.
+func parseTemplate(text string, paint painter) (*template.Template, error) {
+	if text == "" {
+		text = DefaultTemplate
+	}
+	return template.New("corelog").Funcs(templateFuncs(paint)).Parse(text)
+}
+
+// templateHandler is an slog.Handler that writes records using a text/template.
+type templateHandler struct {
+	name     string
+	config   Config
+	output   io.Writer
+	level    slog.Leveler
+	keys     Keys
+	template *template.Template
+	attrs    attrGroups
+}
+
+var _ (slog.Handler) = (*templateHandler)(nil)
+
+func newTemplateHandler(config Config, name string, output io.Writer) *templateHandler {
+	paint := newPainter(config, detectColor(config, output))
+	tmpl, err := parseTemplate(config.Template, paint)
+	if err != nil {
+		// default to the default template if
+		// the set value is invalid
+		tmpl, _ = parseTemplate(DefaultTemplate, paint)
+	}
+	return &templateHandler{
+		name:     name,
+		config:   config,
+		output:   output,
+		level:    configLevel(config),
+		keys:     config.Keys.withDefaults(),
+		template: tmpl,
+	}
+}
+
+func (h *templateHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *templateHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	other := *h
+	other.attrs = h.attrs.withAttrs(attrs)
+	return &other
+}
+
+func (h *templateHandler) WithGroup(name string) slog.Handler {
+	other := *h
+	other.attrs = h.attrs.withGroup(name)
+	return &other
+}
+
+func (h *templateHandler) Handle(ctx context.Context, record slog.Record) error {
+	data := templateRecord{
+		Time:  newTemplateTime(h.config, record.Time.Round(0)),
+		Level: record.Level,
+		Name:  h.name,
+		Msg:   record.Message,
+	}
+	// name is part of the template data so
+	// it is removed from the record attributes
+	attrs := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		if attr.Key != h.keys.Name {
+			attrs.AddAttrs(attr)
+		}
+		return true
+	})
+	data.Attrs = h.attrs.resolve(attrs)
+	if h.config.EnableSource && record.PC != 0 {
+		frames := runtime.CallersFrames([]uintptr{record.PC})
+		frame, _ := frames.Next()
+		data.Source = filepath.Base(frame.File) + ":" + strconv.Itoa(frame.Line)
+	}
+
+	var buf bytes.Buffer
+	if err := h.template.Execute(&buf, data); err != nil {
+		return err
+	}
+	line := bytes.TrimRight(buf.Bytes(), " ")
+	_, err := h.output.Write(append(line, '\n'))
+	return err
+}
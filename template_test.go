@@ -0,0 +1,64 @@
+package corelog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateHandler(t *testing.T) {
+	var buf bytes.Buffer
+	config := Config{Template: "{{.Time}} [{{.Level}}] {{.Name}}: {{.Msg}} {{.Attrs}}"}
+	handler := newTemplateHandler(config, "test", &buf)
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	record := slog.NewRecord(now, slog.LevelInfo, "message", 0)
+	record.AddAttrs(slog.String(nameKey, "test"), slog.String("key", "some value"))
+
+	err := handler.WithGroup("group").Handle(context.Background(), record)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-01-02T03:04:05Z [INFO] test: message group.key=\"some value\"\n", buf.String())
+}
+
+func TestTemplateHandlerWithFuncs(t *testing.T) {
+	var buf bytes.Buffer
+	config := Config{Template: `{{time "15:04" .Time}}|{{pad 6 .Level}}|{{padLeft 6 .Name}}|{{upper .Msg}}|{{color "red" .Msg}}`}
+	handler := newTemplateHandler(config, "test", &buf)
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	record := slog.NewRecord(now, slog.LevelError, "message", 0)
+
+	err := handler.Handle(context.Background(), record)
+	require.NoError(t, err)
+	assert.Equal(t, "03:04|ERROR |  test|MESSAGE|message\n", buf.String())
+}
+
+func TestTemplateHandlerWithAttrsRange(t *testing.T) {
+	var buf bytes.Buffer
+	config := Config{Template: `{{.Msg}}{{range .Attrs}} {{.Key}}:{{.Value}}{{end}}`}
+	handler := newTemplateHandler(config, "test", &buf).
+		WithAttrs([]slog.Attr{slog.Int("a", 1)})
+
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, "message", 0)
+	record.AddAttrs(slog.Bool("b", true))
+
+	err := handler.Handle(context.Background(), record)
+	require.NoError(t, err)
+	assert.Equal(t, "message a:1 b:true\n", buf.String())
+}
+
+func TestTemplateHandlerWithInvalidTemplate(t *testing.T) {
+	var buf bytes.Buffer
+	handler := newTemplateHandler(Config{Template: "{{.Msg"}, "test", &buf)
+
+	record := slog.NewRecord(time.Time{}, slog.LevelInfo, "message", 0)
+
+	err := handler.Handle(context.Background(), record)
+	require.NoError(t, err)
+	assert.Equal(t, " INFO test message\n", buf.String())
+}
@@ -0,0 +1,217 @@
+package corelog
+
+import (
+	"hash/fnv"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// ThemeDefault specifies the default theme for dark backgrounds.
+	ThemeDefault = "default"
+	// ThemeLight specifies a theme for light backgrounds.
+	ThemeLight = "light"
+	// ThemeMono specifies a theme that only uses bold text.
+	ThemeMono = "mono"
+)
+
+// Theme contains the colors used by text output.
+//
+// Each value is a color name, a 256 color palette index, or a "#rrggbb"
+// hex value, and an empty value leaves the text uncolored. Colors are
+// converted to the closest color supported by the terminal.
+type Theme struct {
+	// Time is the color of timestamps.
+	Time string
+	// Debug is the color of the debug level.
+	Debug string
+	// Info is the color of the info level.
+	Info string
+	// Warn is the color of the warn level.
+	Warn string
+	// Error is the color of the error level.
+	Error string
+	// Source is the color of source locations.
+	Source string
+	// Name is the color of logger names when name colors are disabled.
+	Name string
+	// Key is the color of attribute keys.
+	Key string
+	// Value is the color of attribute values.
+	Value string
+	// Err is the color of error values.
+	Err string
+	// Stack is the color of stack trace functions.
+	Stack string
+	// Frame is the color of stack trace file locations.
+	Frame string
+	// Tree is the color of group tree branches.
+	Tree string
+}
+
+var (
+	themesMutex sync.RWMutex
+	themes      = map[string]Theme{
+		ThemeDefault: {
+			Time:   "faint",
+			Debug:  "faint",
+			Info:   "bright-green",
+			Warn:   "bright-yellow",
+			Error:  "bright-red",
+			Source: "faint",
+			Name:   "bold",
+			Key:    "cyan",
+			Value:  "bright-white",
+			Err:    "red",
+			Stack:  "yellow",
+			Frame:  "faint",
+			Tree:   "faint",
+		},
+		ThemeLight: {
+			Time:   "gray",
+			Debug:  "gray",
+			Info:   "green",
+			Warn:   "yellow",
+			Error:  "red",
+			Source: "gray",
+			Name:   "bold+blue",
+			Key:    "blue",
+			Value:  "black",
+			Err:    "red",
+			Stack:  "magenta",
+			Frame:  "gray",
+			Tree:   "gray",
+		},
+		ThemeMono: {
+			Warn:  "bold",
+			Error: "bold",
+			Name:  "bold",
+			Key:   "bold",
+			Err:   "bold",
+			Stack: "bold",
+		},
+	}
+)
+
+// nameColors contains the 256 color palette indexes that logger names
+// are assigned from. They are converted to the closest basic colors on
+// terminals that do not support 256 colors.
+var nameColors = []string{
+	"33", "39", "45", "49", "78", "113", "149", "178",
+	"208", "203", "170", "141", "111", "215", "147", "218",
+}
+
+// RegisterTheme registers a theme that can be selected by name.
+//
+// Registering a theme with an existing name replaces the existing theme.
+func RegisterTheme(name string, theme Theme) {
+	themesMutex.Lock()
+	defer themesMutex.Unlock()
+	themes[strings.ToLower(name)] = theme
+	registrations.Add(1)
+}
+
+// getTheme returns the theme with the given name.
+func getTheme(name string) Theme {
+	themesMutex.RLock()
+	defer themesMutex.RUnlock()
+
+	if theme, ok := themes[strings.ToLower(name)]; ok {
+		return theme
+	}
+	// default to the default theme if no value
+	// is set or the set value is invalid
+	return themes[ThemeDefault]
+}
+
+// hasTheme returns true if a theme with the given name is registered.
+func hasTheme(name string) bool {
+	themesMutex.RLock()
+	defer themesMutex.RUnlock()
+
+	_, ok := themes[strings.ToLower(name)]
+	return ok
+}
+
+// Level returns the color of the given level.
+func (t Theme) Level(level slog.Level) string {
+	switch {
+	case level < slog.LevelInfo:
+		return t.Debug
+	case level < slog.LevelWarn:
+		return t.Info
+	case level < slog.LevelError:
+		return t.Warn
+	default:
+		return t.Error
+	}
+}
+
+// nameColor returns a stable color for the given logger name.
+func nameColor(name string) string {
+	hash := fnv.New32a()
+	hash.Write([]byte(name))
+	return nameColors[hash.Sum32()%uint32(len(nameColors))]
+}
+
+// painter colors text using a theme if color is enabled.
+type painter struct {
+	theme     Theme
+	depth     colorDepth
+	nameColor bool
+}
+
+func newPainter(config Config, depth colorDepth) painter {
+	return painter{
+		theme:     getTheme(config.Theme),
+		depth:     depth,
+		nameColor: config.EnableNameColor,
+	}
+}
+
+// paint wraps the text in the color if color is enabled.
+func (p painter) paint(text string, color string) string {
+	if color == "" {
+		return text
+	}
+	return colorize(text, color, p.depth)
+}
+
+// name colors the logger name with its stable color if
+// name colors are enabled, or the theme color otherwise.
+func (p painter) name(name string) string {
+	if p.nameColor {
+		return p.paint(name, nameColor(name))
+	}
+	return p.paint(name, p.theme.Name)
+}
+
+// level colors the level label with the theme level color.
+func (p painter) level(level slog.Level) string {
+	return p.paint(levelLabel(level), p.theme.Level(level))
+}
+
+// levelLabel returns a short label for the level.
+func levelLabel(level slog.Level) string {
+	var label string
+	var delta slog.Level
+	switch {
+	case level < slog.LevelInfo:
+		label, delta = "DBG", level-slog.LevelDebug
+	case level < slog.LevelWarn:
+		label, delta = "INF", level-slog.LevelInfo
+	case level < slog.LevelError:
+		label, delta = "WRN", level-slog.LevelWarn
+	default:
+		label, delta = "ERR", level-slog.LevelError
+	}
+	if delta > 0 {
+		return label + "+" + strconv.Itoa(int(delta))
+	}
+	if delta < 0 {
+		return label + strconv.Itoa(int(delta))
+	}
+	return label
+}
@@ -0,0 +1,72 @@
+package corelog
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTheme(t *testing.T) {
+	assert.Equal(t, themes[ThemeLight], getTheme("LIGHT"))
+	assert.Equal(t, themes[ThemeDefault], getTheme(""))
+	assert.Equal(t, themes[ThemeDefault], getTheme("invalid"))
+}
+
+func TestRegisterTheme(t *testing.T) {
+	theme := Theme{Info: "blue"}
+	RegisterTheme("Custom", theme)
+	assert.Equal(t, theme, getTheme("custom"))
+}
+
+func TestRegisterThemeAfterLogging(t *testing.T) {
+	var output bytes.Buffer
+	registry := NewRegistry(Config{Theme: "registered-later"})
+	registry.SetOutput(OutputStderr, &output)
+	logger := registry.NewLogger("theme")
+	cache := logger.handler.(*namedHandler).cache
+
+	logger.Info("first")
+	first := cache.Load()
+	RegisterTheme("registered-later", Theme{Info: "blue"})
+	logger.Info("second")
+	assert.NotSame(t, first, cache.Load())
+}
+
+func TestThemeLevel(t *testing.T) {
+	theme := Theme{Debug: "a", Info: "b", Warn: "c", Error: "d"}
+	assert.Equal(t, "a", theme.Level(slog.LevelDebug))
+	assert.Equal(t, "b", theme.Level(slog.LevelInfo))
+	assert.Equal(t, "c", theme.Level(slog.LevelWarn))
+	assert.Equal(t, "d", theme.Level(slog.LevelError))
+}
+
+func TestNameColorIsStable(t *testing.T) {
+	assert.Equal(t, nameColor("net"), nameColor("net"))
+	assert.Contains(t, nameColors, nameColor("net"))
+}
+
+func TestPainter(t *testing.T) {
+	paint := newPainter(Config{Theme: ThemeMono}, color16)
+	assert.Equal(t, "\033[1mERR\033[0m", paint.level(slog.LevelError))
+	assert.Equal(t, "INF", paint.level(slog.LevelInfo))
+	assert.Equal(t, "\033[1mnet\033[0m", paint.name("net"))
+
+	paint = newPainter(Config{EnableNameColor: true}, color256)
+	assert.Equal(t, colorize("net", nameColor("net"), color256), paint.name("net"))
+
+	paint = newPainter(Config{EnableNameColor: true}, colorNone)
+	assert.Equal(t, "net", paint.name("net"))
+	assert.Equal(t, "ERR", paint.level(slog.LevelError))
+}
+
+func TestLevelLabel(t *testing.T) {
+	assert.Equal(t, "DBG", levelLabel(slog.LevelDebug))
+	assert.Equal(t, "INF", levelLabel(slog.LevelInfo))
+	assert.Equal(t, "WRN", levelLabel(slog.LevelWarn))
+	assert.Equal(t, "ERR", levelLabel(slog.LevelError))
+	assert.Equal(t, "ERR+2", levelLabel(slog.LevelError+2))
+	assert.Equal(t, "DBG+3", levelLabel(slog.LevelInfo-1))
+	assert.Equal(t, "DBG-4", levelLabel(slog.LevelDebug-4))
+}
@@ -0,0 +1,109 @@
+package corelog
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// TimeFormatRFC3339 specifies RFC3339 timestamps.
+	TimeFormatRFC3339 = "rfc3339"
+	// TimeFormatRFC3339Nano specifies RFC3339 timestamps with nanoseconds.
+	TimeFormatRFC3339Nano = "rfc3339nano"
+	// TimeFormatUnix specifies unix timestamps in seconds.
+	TimeFormatUnix = "unix"
+	// TimeFormatUnixMilli specifies unix timestamps in milliseconds.
+	TimeFormatUnixMilli = "unixmilli"
+	// TimeFormatUnixNano specifies unix timestamps in nanoseconds.
+	TimeFormatUnixNano = "unixnano"
+	// TimeFormatNone specifies that timestamps are omitted.
+	TimeFormatNone = "none"
+	// TimeZoneLocal specifies timestamps in the local time zone.
+	TimeZoneLocal = "local"
+	// TimeZoneUTC specifies timestamps in the UTC time zone.
+	TimeZoneUTC = "utc"
+)
+
+// locations is a cache of loaded time zone locations.
+var locations sync.Map
+
+// timeLocation returns the location for the given time zone.
+//
+// The zone can be local, utc, or an IANA time zone name.
+func timeLocation(zone string) *time.Location {
+	switch strings.ToLower(zone) {
+	case "", TimeZoneLocal:
+		return time.Local
+	case TimeZoneUTC:
+		return time.UTC
+	}
+	if loc, ok := locations.Load(zone); ok {
+		return loc.(*time.Location)
+	}
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		// default to local if the
+		// set value is invalid
+		loc = time.Local
+	}
+	locations.Store(zone, loc)
+	return loc
+}
+
+// timeValue returns the value of the time for the config time format
+// and time zone, or false if the time should be omitted.
+//
+// An empty time format returns a time value so that handlers
+// can use their default format.
+func timeValue(config Config, t time.Time) (slog.Value, bool) {
+	t = t.In(timeLocation(config.TimeZone))
+	switch strings.ToLower(config.TimeFormat) {
+	case "":
+		return slog.TimeValue(t), true
+	case TimeFormatNone:
+		return slog.Value{}, false
+	case TimeFormatRFC3339:
+		return slog.StringValue(t.Format(time.RFC3339)), true
+	case TimeFormatRFC3339Nano:
+		return slog.StringValue(t.Format(time.RFC3339Nano)), true
+	case TimeFormatUnix:
+		return slog.Int64Value(t.Unix()), true
+	case TimeFormatUnixMilli:
+		return slog.Int64Value(t.UnixMilli()), true
+	case TimeFormatUnixNano:
+		return slog.Int64Value(t.UnixNano()), true
+	default:
+		// all other values are custom layouts
+		return slog.StringValue(t.Format(config.TimeFormat)), true
+	}
+}
+
+// replaceTime returns the time attribute for the config time
+// format and time zone, or an empty attribute if it is omitted.
+func replaceTime(config Config, attr slog.Attr) slog.Attr {
+	if attr.Value.Kind() != slog.KindTime {
+		return attr
+	}
+	value, ok := timeValue(config, attr.Value.Time())
+	if !ok {
+		return slog.Attr{}
+	}
+	return slog.Attr{Key: attr.Key, Value: value}
+}
+
+// timeText returns the text of the time for the config time format and
+// time zone, or false if the time should be omitted.
+//
+// The given layout is used when no time format is set.
+func timeText(config Config, t time.Time, layout string) (string, bool) {
+	value, ok := timeValue(config, t)
+	if !ok {
+		return "", false
+	}
+	if value.Kind() == slog.KindTime {
+		return value.Time().Format(layout), true
+	}
+	return value.String(), true
+}
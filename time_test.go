@@ -0,0 +1,56 @@
+package corelog
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeLocation(t *testing.T) {
+	assert.Equal(t, time.Local, timeLocation(""))
+	assert.Equal(t, time.Local, timeLocation(TimeZoneLocal))
+	assert.Equal(t, time.UTC, timeLocation("UTC"))
+	assert.Equal(t, time.Local, timeLocation("Invalid/Zone"))
+	assert.Equal(t, "America/New_York", timeLocation("America/New_York").String())
+}
+
+func TestTimeValue(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 6000000, time.UTC)
+
+	tests := []struct {
+		format   string
+		expected slog.Value
+	}{
+		{"", slog.TimeValue(now)},
+		{TimeFormatRFC3339, slog.StringValue("2024-01-02T03:04:05Z")},
+		{TimeFormatRFC3339Nano, slog.StringValue("2024-01-02T03:04:05.006Z")},
+		{TimeFormatUnix, slog.Int64Value(now.Unix())},
+		{TimeFormatUnixMilli, slog.Int64Value(now.UnixMilli())},
+		{TimeFormatUnixNano, slog.Int64Value(now.UnixNano())},
+		{"15:04:05", slog.StringValue("03:04:05")},
+	}
+	for _, test := range tests {
+		value, ok := timeValue(Config{TimeFormat: test.format, TimeZone: TimeZoneUTC}, now)
+		assert.True(t, ok)
+		assert.True(t, test.expected.Equal(value), test.format)
+	}
+
+	_, ok := timeValue(Config{TimeFormat: TimeFormatNone}, now)
+	assert.False(t, ok)
+}
+
+func TestReplaceTime(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	config := Config{TimeFormat: TimeFormatUnix}
+
+	attr := replaceTime(config, slog.Time(slog.TimeKey, now))
+	assert.Equal(t, slog.Int64(slog.TimeKey, now.Unix()), attr)
+
+	attr = replaceTime(config, slog.String(slog.TimeKey, "value"))
+	assert.Equal(t, slog.String(slog.TimeKey, "value"), attr)
+
+	attr = replaceTime(Config{TimeFormat: TimeFormatNone}, slog.Time(slog.TimeKey, now))
+	assert.Equal(t, slog.Attr{}, attr)
+}
@@ -0,0 +1,215 @@
+package corelog
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrUnknownKey is returned for config keys that do not exist.
+	ErrUnknownKey = errors.New("unknown config key")
+	// ErrInvalidPair is returned for config override pairs that are not separated by "=".
+	ErrInvalidPair = errors.New("invalid key value pair")
+	// ErrMissingName is returned for config overrides without a logger name.
+	ErrMissingName = errors.New("missing logger name")
+	// ErrInvalidPattern is returned for config override names
+	// that are invalid glob patterns or regular expressions.
+	ErrInvalidPattern = errors.New("invalid name pattern")
+)
+
+// ConfigError is an error for an invalid config key or value.
+type ConfigError struct {
+	// Source is the environment variable or file that contains the
+	// invalid value, or is empty if the value was set programmatically.
+	Source string
+	// Column is the position of the invalid text in config
+	// overrides text starting at 1, or 0 if it is not known.
+	Column int
+	// Name is the logger name of the config override
+	// that contains the invalid value.
+	Name string
+	// Key is the config key of the invalid value.
+	Key string
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *ConfigError) Error() string {
+	var parts []string
+	if e.Source != "" {
+		parts = append(parts, e.Source)
+	}
+	if e.Column > 0 {
+		parts = append(parts, "column "+strconv.Itoa(e.Column))
+	}
+	if e.Name != "" {
+		parts = append(parts, fmt.Sprintf("override %q", e.Name))
+	}
+	if e.Key != "" {
+		parts = append(parts, fmt.Sprintf("key %q", e.Key))
+	}
+	return strings.Join(append(parts, e.Err.Error()), ": ")
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// Validate returns an error for every config value that is not valid.
+//
+// Empty values are valid and use the default value.
+func (c Config) Validate() error {
+	var errs []error
+	for _, field := range configFields {
+		if field.check == nil {
+			continue
+		}
+		if err := field.check(&c); err != nil {
+			errs = append(errs, &ConfigError{Key: field.key, Err: err})
+		}
+	}
+	errs = append(errs, checkRegistered(&c, nil, "", "")...)
+	return errors.Join(errs...)
+}
+
+// CheckConfig returns an error for every invalid config value that is set by
+// environment variables, LOG_OVERRIDES, and LOG_CONFIG_FILE, or the variables
+// with the prefix set by SetEnvPrefix.
+//
+// Invalid values are otherwise silently ignored or replaced with default
+// values, so applications should call CheckConfig at startup to report
+// them or to exit.
+//
+// Formats and themes must be registered before CheckConfig is called.
+func CheckConfig() error {
+	var errs []error
+	for _, field := range configFields {
+		if field.env == "" {
+			continue
+		}
+		if name, value := getEnv(field.env); value != "" {
+			var config Config
+			if err := setConfigField(&config, field, value); err != nil {
+				errs = append(errs, &ConfigError{Source: name, Err: err})
+			} else if field.registered != nil {
+				if err := field.registered(&config); err != nil {
+					errs = append(errs, &ConfigError{Source: name, Err: err})
+				}
+			}
+		}
+	}
+	if name, text := getEnv(envAttrs); text != "" {
+		if _, err := parseAttrs(text, nil); err != nil {
+			errs = append(errs, &ConfigError{Source: name, Err: err})
+		}
+	}
+	if _, path := getEnv(envConfigFile); path != "" {
+		config, overrides, err := readConfigFile(path)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		errs = append(errs, checkRegistered(&config, nil, path, "")...)
+		for _, override := range overrides {
+			errs = append(errs, checkRegistered(&override.Config, override.Fields, path, override.Name)...)
+		}
+	}
+	if name, text := getEnv(envOverrides); text != "" {
+		overrides, err := parseConfigOverrides(text, name)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for _, override := range overrides {
+			errs = append(errs, checkRegistered(&override.Config, override.Fields, name, override.Name)...)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkRegistered returns an error for every value of the fields with the
+// given keys, or of all fields if keys is nil, that is not registered.
+func checkRegistered(config *Config, keys []string, source string, name string) []error {
+	var errs []error
+	for _, field := range configFields {
+		if field.registered == nil || (keys != nil && !slices.Contains(keys, field.key)) {
+			continue
+		}
+		if err := field.registered(config); err != nil {
+			errs = append(errs, &ConfigError{Source: source, Name: name, Key: field.key, Err: err})
+		}
+	}
+	return errs
+}
+
+// setConfigField sets the field on the config from the value
+// if it is valid and returns an error otherwise.
+func setConfigField(config *Config, field configField, value string) error {
+	other := *config
+	if err := field.set(&other, value); err != nil {
+		return err
+	}
+	if field.check != nil {
+		if err := field.check(&other); err != nil {
+			return err
+		}
+	}
+	*config = other
+	return nil
+}
+
+// checkLevel returns an error if the level is not valid.
+func checkLevel(level string) error {
+	switch level {
+	case "", LevelDebug, LevelInfo, LevelWarn, LevelError:
+		return nil
+	}
+	return fmt.Errorf("invalid level %q", level)
+}
+
+// checkFormat returns an error if the format is not registered.
+func checkFormat(format string) error {
+	if format == "" || hasFormat(format) {
+		return nil
+	}
+	return fmt.Errorf("invalid format %q", format)
+}
+
+// checkOutput returns an error if the output is not valid.
+func checkOutput(output string) error {
+	switch output {
+	case "", OutputStdout, OutputStderr:
+		return nil
+	}
+	return fmt.Errorf("invalid output %q", output)
+}
+
+// checkTheme returns an error if the theme is not registered.
+func checkTheme(theme string) error {
+	if theme == "" || hasTheme(theme) {
+		return nil
+	}
+	return fmt.Errorf("invalid theme %q", theme)
+}
+
+// checkTimeZone returns an error if the time zone cannot be loaded.
+func checkTimeZone(zone string) error {
+	switch strings.ToLower(zone) {
+	case "", TimeZoneLocal, TimeZoneUTC:
+		return nil
+	}
+	if _, err := time.LoadLocation(zone); err != nil {
+		return fmt.Errorf("invalid time zone %q", zone)
+	}
+	return nil
+}
+
+// checkTemplate returns an error if the template cannot be parsed.
+func checkTemplate(text string) error {
+	if _, err := parseTemplate(text, painter{}); err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+	return nil
+}
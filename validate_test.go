@@ -0,0 +1,122 @@
+package corelog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConfigOverrides(t *testing.T) {
+	overrides, err := ParseConfigOverrides("net,level=error,source=true; core , format=json;;")
+	require.NoError(t, err)
+	require.Len(t, overrides, 2)
+
+	assert.Equal(t, "net", overrides[0].Name)
+	assert.Equal(t, LevelError, overrides[0].Config.Level)
+	assert.Equal(t, true, overrides[0].Config.EnableSource)
+	assert.Equal(t, "core", overrides[1].Name)
+	assert.Equal(t, FormatJSON, overrides[1].Config.Format)
+}
+
+func TestParseConfigOverridesWithErrors(t *testing.T) {
+	text := "net,levle=error,source=maybe,format=json;core, invalid,level=verbose;,level=info"
+	overrides, err := ParseConfigOverrides(text)
+	require.Len(t, overrides, 2)
+	assert.Equal(t, FormatJSON, overrides[0].Config.Format)
+	assert.Equal(t, false, overrides[0].Config.EnableSource)
+	assert.Equal(t, "", overrides[1].Config.Level)
+
+	var errs []*ConfigError
+	for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var configErr *ConfigError
+		require.True(t, errors.As(err, &configErr))
+		errs = append(errs, configErr)
+	}
+	require.Len(t, errs, 5)
+
+	assert.Equal(t, &ConfigError{Column: 5, Name: "net", Key: "levle", Err: ErrUnknownKey}, errs[0])
+	assert.EqualError(t, errs[1], `column 17: override "net": key "source": invalid boolean "maybe"`)
+	assert.Equal(t, 48, errs[2].Column)
+	assert.ErrorIs(t, errs[2], ErrInvalidPair)
+	assert.EqualError(t, errs[3], `column 56: override "core": key "level": invalid level "verbose"`)
+	assert.Equal(t, &ConfigError{Column: 70, Err: ErrMissingName}, errs[4])
+
+	assert.Equal(t, "levle", text[errs[0].Column-1:errs[0].Column+4])
+	assert.Equal(t, "invalid", text[errs[2].Column-1:errs[2].Column+6])
+}
+
+func TestConfigValidate(t *testing.T) {
+	assert.NoError(t, Config{}.Validate())
+	assert.NoError(t, Config{
+		Level:    LevelDebug,
+		Format:   FormatPretty,
+		Output:   OutputStdout,
+		Theme:    ThemeLight,
+		TimeZone: "America/New_York",
+		Template: "{{.Msg}}",
+	}.Validate())
+
+	err := Config{
+		Level:    "verbose",
+		Format:   "xml",
+		Output:   "/var/log/node.log",
+		Theme:    "neon",
+		TimeZone: "Mars/Olympus_Mons",
+		Template: "{{.Msg",
+	}.Validate()
+	assert.ErrorContains(t, err, `key "level": invalid level "verbose"`)
+	assert.ErrorContains(t, err, `key "format": invalid format "xml"`)
+	assert.ErrorContains(t, err, `key "output": invalid output "/var/log/node.log"`)
+	assert.ErrorContains(t, err, `key "theme": invalid theme "neon"`)
+	assert.ErrorContains(t, err, `key "time-zone": invalid time zone "Mars/Olympus_Mons"`)
+	assert.ErrorContains(t, err, `key "template": invalid template`)
+}
+
+func TestCheckConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("level: verbose\n"), 0o644))
+
+	t.Setenv("LOG_LEVEL", "verbose")
+	t.Setenv("LOG_SOURCE", "maybe")
+	t.Setenv("LOG_KEYS", "zap")
+	t.Setenv("LOG_OVERRIDES", "net,levle=info")
+	t.Setenv("LOG_CONFIG_FILE", path)
+
+	err := CheckConfig()
+	assert.ErrorContains(t, err, `LOG_LEVEL: invalid level "verbose"`)
+	assert.ErrorContains(t, err, `LOG_SOURCE: invalid boolean "maybe"`)
+	assert.ErrorContains(t, err, `LOG_KEYS: invalid keys preset "zap"`)
+	assert.ErrorContains(t, err, `LOG_OVERRIDES: column 5: override "net": key "levle": unknown config key`)
+	assert.ErrorContains(t, err, path+`: key "level": invalid level "verbose"`)
+}
+
+func TestCheckConfigWithValidEnv(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "ERROR")
+	t.Setenv("LOG_OVERRIDES", "net,level=info")
+	assert.NoError(t, CheckConfig())
+}
+
+func TestUnregisteredFormatAndTheme(t *testing.T) {
+	t.Setenv("LOG_FORMAT", "Later")
+	t.Setenv("LOG_THEME", "later")
+	t.Setenv("LOG_OVERRIDES", "net,format=later,theme=later")
+
+	// formats and themes can be registered after the config is parsed
+	config := DefaultConfig()
+	assert.Equal(t, "later", config.Format)
+	assert.Equal(t, "later", config.Theme)
+	overrides, err := ParseConfigOverrides("net,format=later,theme=later")
+	require.NoError(t, err)
+	assert.Equal(t, "later", overrides[0].Config.Format)
+	assert.Equal(t, "later", overrides[0].Config.Theme)
+
+	err = CheckConfig()
+	assert.ErrorContains(t, err, `LOG_FORMAT: invalid format "later"`)
+	assert.ErrorContains(t, err, `LOG_THEME: invalid theme "later"`)
+	assert.ErrorContains(t, err, `LOG_OVERRIDES: override "net": key "format": invalid format "later"`)
+	assert.ErrorContains(t, err, `LOG_OVERRIDES: override "net": key "theme": invalid theme "later"`)
+}
@@ -0,0 +1,136 @@
+package corelog
+
+import (
+	"crypto/sha256"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultWatchInterval is the default interval between config file checks.
+const DefaultWatchInterval = 5 * time.Second
+
+// internalLogger is used to log config reloads and failures.
+var internalLogger = NewLogger("corelog")
+
+// WatchConfigFile watches the config file at the given path
+// for changes and loads it into the default registry.
+func WatchConfigFile(path string, interval time.Duration) (stop func()) {
+	return defaultRegistry.WatchConfigFile(path, interval)
+}
+
+// WatchConfigFile checks the config file at the given path for changes at the
+// given interval and loads the config and config overrides from the file when
+// its contents change. Call LoadConfigFile first to load the current contents.
+//
+// The file is polled so that watching works on all platforms and file systems.
+// If the changed file cannot be loaded the current config is kept and a warning
+// is logged. The returned function stops watching the file.
+func (r *Registry) WatchConfigFile(path string, interval time.Duration) (stop func()) {
+	watcher := newConfigWatcher(path, r.NewLogger("corelog"), func(config Config, overrides map[string]ConfigOverride) {
+		r.replaceConfig(config, overrides)
+	})
+	return watchConfigFile(watcher, interval)
+}
+
+// watchConfigFile checks the watched file at the given interval
+// and returns a function that stops watching the file.
+func watchConfigFile(watcher *configWatcher, interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		for {
+			select {
+			case <-ticker.C:
+				watcher.check()
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(done)
+			<-exited
+		})
+	}
+}
+
+// configWatcher detects changes to a config file.
+type configWatcher struct {
+	path   string
+	logger *Logger
+	// load replaces the config with the loaded config and overrides
+	load    func(config Config, overrides map[string]ConfigOverride)
+	modTime time.Time
+	size    int64
+	sum     [sha256.Size]byte
+	// warning is the last logged warning, so that
+	// the same failure is only logged once
+	warning string
+}
+
+func newConfigWatcher(path string, logger *Logger, load func(Config, map[string]ConfigOverride)) *configWatcher {
+	watcher := &configWatcher{path: path, logger: logger, load: load}
+	if info, err := os.Stat(path); err == nil {
+		watcher.modTime = info.ModTime()
+		watcher.size = info.Size()
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		watcher.sum = sha256.Sum256(data)
+	}
+	return watcher
+}
+
+// check loads the config file if its modification time or size
+// has changed and its contents are different.
+func (w *configWatcher) check() {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		// reset the file info so that the
+		// file is read when it is restored
+		w.modTime, w.size = time.Time{}, 0
+		w.warn(err)
+		return
+	}
+	if info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+		return
+	}
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		w.warn(err)
+		return
+	}
+	w.modTime, w.size = info.ModTime(), info.Size()
+
+	sum := sha256.Sum256(data)
+	if sum == w.sum {
+		w.warning = ""
+		return
+	}
+	w.sum = sum
+
+	config, overrides, err := parseConfigFile(w.path, data)
+	if err != nil {
+		w.warn(err)
+		return
+	}
+	w.warning = ""
+	w.load(config, overrides)
+}
+
+// warn logs a warning for the error unless it was the last logged warning.
+func (w *configWatcher) warn(err error) {
+	if err.Error() == w.warning {
+		return
+	}
+	w.warning = err.Error()
+	w.logger.Warn("failed to reload config file", String("path", w.path), String("error", err.Error()))
+}
@@ -0,0 +1,125 @@
+package corelog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigWatcherCheck(t *testing.T) {
+	restoreConfig(t)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("level: info\n"), 0o644))
+	require.NoError(t, LoadConfigFile(path))
+
+	watcher := newConfigWatcher(path, internalLogger, replaceEnvConfig)
+	watcher.check()
+	assert.Equal(t, LevelInfo, GetConfig("").Level)
+
+	require.NoError(t, os.WriteFile(path, []byte("level: error\noverrides:\n  watch:\n    format: json\n"), 0o644))
+	watcher.check()
+	assert.Equal(t, LevelError, GetConfig("").Level)
+	assert.Equal(t, FormatJSON, GetConfig("watch").Format)
+}
+
+func TestConfigWatcherCheckKeepsEnvOverrides(t *testing.T) {
+	restoreConfig(t)
+	t.Setenv("LOG_OVERRIDES", "watch,level=debug")
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("level: info\n"), 0o644))
+	watcher := newConfigWatcher(path, internalLogger, replaceEnvConfig)
+
+	require.NoError(t, os.WriteFile(path, []byte("level: error\noverrides:\n  file:\n    format: json\n"), 0o644))
+	watcher.check()
+	assert.Equal(t, LevelError, GetConfig("").Level)
+	assert.Equal(t, LevelDebug, GetConfig("watch").Level)
+	assert.Equal(t, FormatJSON, GetConfig("file").Format)
+}
+
+func TestConfigWatcherCheckKeepsConfigOnError(t *testing.T) {
+	restoreConfig(t)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("level: error\n"), 0o644))
+	require.NoError(t, LoadConfigFile(path))
+
+	watcher := newConfigWatcher(path, internalLogger, replaceEnvConfig)
+	require.NoError(t, os.WriteFile(path, []byte("level: info\nlevle: info\n"), 0o644))
+	watcher.check()
+	assert.Equal(t, LevelError, GetConfig("").Level)
+	assert.Contains(t, watcher.warning, `config.yaml: key "levle": unknown config key`)
+
+	require.NoError(t, os.Remove(path))
+	watcher.check()
+	assert.Equal(t, LevelError, GetConfig("").Level)
+	assert.Contains(t, watcher.warning, "no such file")
+
+	require.NoError(t, os.WriteFile(path, []byte("level: info\n"), 0o644))
+	watcher.check()
+	assert.Equal(t, LevelInfo, GetConfig("").Level)
+	assert.Equal(t, "", watcher.warning)
+}
+
+func TestWatchConfigFile(t *testing.T) {
+	restoreConfig(t)
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"level": "info"}`), 0o644))
+	require.NoError(t, LoadConfigFile(path))
+
+	stop := WatchConfigFile(path, 10*time.Millisecond)
+	t.Cleanup(stop)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"level": "error"}`), 0o644))
+	require.Eventually(t, func() bool {
+		return GetConfig("").Level == LevelError
+	}, time.Second, 10*time.Millisecond)
+
+	stop()
+	require.NoError(t, os.WriteFile(path, []byte(`{"level": "warning"}`), 0o644))
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, LevelError, GetConfig("").Level)
+}
+
+func TestWatchConfigFileWithoutEnvOverrides(t *testing.T) {
+	restoreConfig(t)
+	t.Setenv("LOG_OVERRIDES", "watch.env,level=debug")
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"level": "info"}`), 0o644))
+	require.NoError(t, LoadConfigFile(path))
+
+	stop := WatchConfigFile(path, 10*time.Millisecond)
+	t.Cleanup(stop)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"level": "error"}`), 0o644))
+	require.Eventually(t, func() bool {
+		return GetConfig("").Level == LevelError
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, LevelError, GetConfig("watch.env").Level)
+}
+
+func TestRegistryWatchConfigFile(t *testing.T) {
+	restoreConfig(t)
+	SetConfig(Config{Level: LevelWarn})
+	registry := NewRegistry(Config{Level: LevelInfo})
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"level": "info"}`), 0o644))
+
+	stop := registry.WatchConfigFile(path, 10*time.Millisecond)
+	t.Cleanup(stop)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"level": "error", "overrides": {"net": {"format": "json"}}}`), 0o644))
+	require.Eventually(t, func() bool {
+		return registry.GetConfig("").Level == LevelError
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, FormatJSON, registry.GetConfig("net").Format)
+	assert.Equal(t, LevelWarn, GetConfig("").Level)
+}